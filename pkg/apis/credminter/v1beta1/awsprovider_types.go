@@ -0,0 +1,73 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&AWSProviderSpec{}, &AWSProviderStatus{})
+}
+
+// AWSProviderSpec is a RawExtension inside of a CredentialsRequest used to define the
+// permissions needed by an AWS IAM user to satisfy a CredentialsRequest.
+type AWSProviderSpec struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// StatementEntries is a list of policy statements which should be associated with the
+	// IAM user created for this CredentialsRequest.
+	StatementEntries []StatementEntry `json:"statementEntries"`
+}
+
+// StatementEntry models one entry in a statement of an IAM policy document, as seen here:
+// https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_elements.html
+type StatementEntry struct {
+	// Effect indicates if this permission should be allowed or denied.
+	Effect string `json:"effect"`
+
+	// Action describes the particular AWS service actions that should be allowed or denied.
+	Action []string `json:"action"`
+
+	// Resource specifies which AWS resource this permission applies to.
+	Resource string `json:"resource"`
+
+	// PolicyCondition specifies under which condition(s) this statement applies, scoping the
+	// permission tighter than Action/Resource alone allow. Rendered as the statement's
+	// "Condition" block in the generated IAM policy document, e.g.:
+	//   {"StringEquals": {"aws:RequestTag/owner": "openshift"}}
+	// +optional
+	PolicyCondition IAMPolicyCondition `json:"policyCondition,omitempty"`
+}
+
+// IAMPolicyCondition represents the "Condition" block of an IAM policy statement: a map of
+// condition operator (e.g. "StringEquals") to a map of condition key/value pairs.
+type IAMPolicyCondition map[string]map[string]string
+
+// AWSProviderStatus contains the status of the credentials minted for a CredentialsRequest in AWS
+type AWSProviderStatus struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// User is the name of the IAM user that was created for this CredentialsRequest.
+	User string `json:"user"`
+
+	// Expiration is the time at which the credentials in the target Secret expire and must be
+	// refreshed. Only set for credentials obtained via sts:AssumeRole, IAM user access keys do
+	// not expire.
+	// +optional
+	Expiration *metav1.Time `json:"expiration,omitempty"`
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&AWSSTSProviderSpec{})
+}
+
+// AWSSTSProviderSpec is a RawExtension inside of a CredentialsRequest used to define a
+// CredentialsRequest that should be satisfied with short-lived credentials obtained via
+// sts:AssumeRole, rather than a long-lived IAM user access key.
+type AWSSTSProviderSpec struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// RoleARN is the ARN of the IAM role the operator should assume on behalf of this
+	// CredentialsRequest.
+	RoleARN string `json:"roleARN"`
+
+	// ExternalID is passed to sts:AssumeRole as the ExternalId parameter, for roles whose trust
+	// policy requires one.
+	// +optional
+	ExternalID string `json:"externalID,omitempty"`
+
+	// SessionDuration is the lifetime requested for the assumed role session, in seconds. AWS
+	// bounds this to the role's configured maximum session duration (between 1 and 12 hours).
+	// Defaults to 1 hour if unset.
+	// +optional
+	SessionDuration int64 `json:"sessionDuration,omitempty"`
+
+	// StatementEntries is used as an inline session policy passed to AssumeRole, further scoping
+	// down the permissions of the assumed role for the duration of the session.
+	// +optional
+	StatementEntries []StatementEntry `json:"statementEntries,omitempty"`
+}
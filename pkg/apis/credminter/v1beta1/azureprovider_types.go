@@ -0,0 +1,70 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&AzureProviderSpec{}, &AzureProviderStatus{})
+}
+
+// AzureProviderSpec is a RawExtension inside of a CredentialsRequest used to define the
+// permissions needed by an Azure AD service principal to satisfy a CredentialsRequest.
+type AzureProviderSpec struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// SubscriptionID is the Azure subscription the service principal's role assignments should
+	// be scoped to.
+	SubscriptionID string `json:"subscriptionID"`
+
+	// RoleDefinitions is the list of Azure role definition IDs (the GUID portion of
+	// "/subscriptions/<id>/providers/Microsoft.Authorization/roleDefinitions/<guid>", e.g. the
+	// built-in "Contributor" role's GUID) that should be assigned to the service principal
+	// created for this CredentialsRequest.
+	RoleDefinitions []string `json:"roleDefinitions"`
+
+	// Scopes further restricts the created role assignments to specific resource IDs within
+	// SubscriptionID. If empty, role assignments are scoped to the subscription itself.
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// AzureProviderStatus contains the status of the credentials minted for a CredentialsRequest in
+// Azure.
+type AzureProviderStatus struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// ServicePrincipalName is the display name of the Azure AD service principal created for
+	// this CredentialsRequest.
+	ServicePrincipalName string `json:"servicePrincipalName"`
+
+	// AppID is the application (client) ID of the Azure AD application backing the service
+	// principal created for this CredentialsRequest. This is the value written to the target
+	// Secret for consumers to authenticate with.
+	AppID string `json:"appID"`
+
+	// ObjectID is the Azure AD object ID of the application backing the service principal
+	// created for this CredentialsRequest. Unlike AppID, this is the identifier the graphrbac
+	// API requires to look up or delete the application.
+	ObjectID string `json:"objectID"`
+
+	// RoleAssignmentIDs is the list of resource IDs of the role assignments created for the
+	// service principal, so that they can be cleaned up when this CredentialsRequest is deleted.
+	RoleAssignmentIDs []string `json:"roleAssignmentIDs,omitempty"`
+}
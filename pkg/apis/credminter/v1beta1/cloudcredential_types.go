@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&CloudCredential{}, &CloudCredentialList{})
+}
+
+// CloudCredentialOperatorConfigName is the name of the single, cluster-scoped CloudCredential
+// object that controls this operator's behavior.
+const CloudCredentialOperatorConfigName = "cluster"
+
+// CredentialsMode controls how CredentialsRequests are satisfied by this operator.
+type CredentialsMode string
+
+const (
+	// CredentialsModeDefault defers to auto-detection: Mint if the root credential is capable
+	// of creating IAM users, Passthrough otherwise. The Azure actuator has no equivalent
+	// detection API, so it always treats CredentialsModeDefault as Mint.
+	CredentialsModeDefault CredentialsMode = ""
+
+	// MintCredentialsMode has the operator create a dedicated IAM user/service principal with
+	// a narrowly scoped policy for each CredentialsRequest.
+	MintCredentialsMode CredentialsMode = "Mint"
+
+	// PassthroughCredentialsMode has the operator copy the root credential into each
+	// CredentialsRequest's target Secret, after verifying the root credential satisfies the
+	// request's permissions. Not currently implemented for Azure: there's no Azure API
+	// equivalent to SimulatePrincipalPolicy to verify the root credential's permissions against,
+	// so Azure CredentialsRequests left in Passthrough mode are reported as unprovisioned via a
+	// CredentialsProvisionFailure condition rather than silently minted or passed through.
+	PassthroughCredentialsMode CredentialsMode = "Passthrough"
+
+	// DisabledCredentialsMode has the operator take no action on CredentialsRequests, leaving
+	// it to another component to provision credentials.
+	DisabledCredentialsMode CredentialsMode = "Disabled"
+)
+
+// CloudCredentialSpec defines the desired state of CloudCredential
+type CloudCredentialSpec struct {
+	// CredentialsMode dictates how CredentialsRequests should be satisfied.
+	// +optional
+	CredentialsMode CredentialsMode `json:"credentialsMode,omitempty"`
+}
+
+// CloudCredentialStatus defines the observed state of CloudCredential
+type CloudCredentialStatus struct {
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudCredential is the Schema for the cluster-scoped operator configuration. The only valid
+// name for this object is "cluster".
+type CloudCredential struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudCredentialSpec   `json:"spec,omitempty"`
+	Status CloudCredentialStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudCredentialList contains a list of CloudCredential
+type CloudCredentialList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudCredential `json:"items"`
+}
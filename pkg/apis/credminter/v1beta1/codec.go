@@ -0,0 +1,117 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+// Codec is used to encode/decode the cloud provider specific ProviderSpec and ProviderStatus
+// fields on CredentialsRequest to/from their RawExtension form.
+type Codec struct {
+	scheme  *runtime.Scheme
+	encoder runtime.Encoder
+	decoder runtime.Decoder
+}
+
+// NewCodec creates a new Codec for encoding/decoding provider specs and statuses registered in
+// this package's scheme (AWSProviderSpec, AWSProviderStatus, and friends).
+func NewCodec() (*Codec, error) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	codecFactory := serializer.NewCodecFactory(scheme)
+	serializerInfo, ok := runtime.SerializerInfoForMediaType(codecFactory.SupportedMediaTypes(), runtime.ContentTypeJSON)
+	if !ok {
+		return nil, fmt.Errorf("unable to locate JSON serializer")
+	}
+	return &Codec{
+		scheme:  scheme,
+		encoder: serializerInfo.Serializer,
+		decoder: codecFactory.UniversalDecoder(),
+	}, nil
+}
+
+// EncodeProviderSpec serializes the given provider-specific spec (e.g. *AWSProviderSpec) into a
+// RawExtension suitable for storing in CredentialsRequestSpec.ProviderSpec. The object's Kind is
+// stamped in before serializing so consumers (e.g. the actuator registry) can later tell provider
+// specs apart without fully decoding them.
+func (c *Codec) EncodeProviderSpec(spec runtime.Object) (*runtime.RawExtension, error) {
+	if err := c.setObjectKind(spec); err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := c.encoder.Encode(spec, &buffer); err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: buffer.Bytes()}, nil
+}
+
+func (c *Codec) setObjectKind(obj runtime.Object) error {
+	kinds, _, err := c.scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+	if len(kinds) == 0 {
+		return fmt.Errorf("no registered kind for %T", obj)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(kinds[0])
+	return nil
+}
+
+// DecodeProviderSpec decodes the given RawExtension into the provider-specific spec passed as
+// out (e.g. &AWSProviderSpec{}).
+func (c *Codec) DecodeProviderSpec(providerSpec *runtime.RawExtension, out runtime.Object) error {
+	if providerSpec == nil {
+		return nil
+	}
+	_, _, err := c.decoder.Decode(providerSpec.Raw, nil, out)
+	return err
+}
+
+// EncodeProviderStatus serializes the given provider-specific status (e.g. *AWSProviderStatus)
+// into a RawExtension suitable for storing in CredentialsRequestStatus.ProviderStatus.
+func (c *Codec) EncodeProviderStatus(status runtime.Object) (*runtime.RawExtension, error) {
+	return c.EncodeProviderSpec(status)
+}
+
+// DecodeProviderStatus decodes the given RawExtension into the provider-specific status passed
+// as out (e.g. &AWSProviderStatus{}).
+func (c *Codec) DecodeProviderStatus(providerStatus *runtime.RawExtension, out runtime.Object) error {
+	return c.DecodeProviderSpec(providerStatus, out)
+}
+
+// ProviderSpecKind returns the Kind stamped into a RawExtension provider spec by
+// EncodeProviderSpec, without fully decoding it into its concrete type. This lets callers (e.g.
+// an actuator dispatching between multiple provider spec types) tell specs apart cheaply.
+func (c *Codec) ProviderSpecKind(providerSpec *runtime.RawExtension) (string, error) {
+	if providerSpec == nil {
+		return "", nil
+	}
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(providerSpec.Raw, &typeMeta); err != nil {
+		return "", err
+	}
+	return typeMeta.Kind, nil
+}
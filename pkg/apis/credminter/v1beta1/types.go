@@ -0,0 +1,124 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func init() {
+	SchemeBuilder.Register(&CredentialsRequest{}, &CredentialsRequestList{})
+}
+
+const (
+	// FinalizerDeprovision is used on CredentialsRequests to ensure we delete the credentials
+	// we provisioned before the API object is allowed to be deleted.
+	FinalizerDeprovision string = "cred-minter.openshift.io/deprovision"
+
+	// AnnotationCredentialsRequest is used on a target Secret to identify the CredentialsRequest
+	// that created and manages it.
+	AnnotationCredentialsRequest string = "cred-minter.openshift.io/credentials-request"
+)
+
+// CredentialsRequestSpec defines the desired state of CredentialsRequest
+type CredentialsRequestSpec struct {
+	// SecretRef points to the secret where the credentials should be written once generated.
+	SecretRef corev1.ObjectReference `json:"secretRef"`
+
+	// ClusterName is the name of the cluster this CredentialsRequest belongs to.
+	ClusterName string `json:"clusterName"`
+
+	// ClusterID is the unique generated ID for the cluster this CredentialsRequest belongs to.
+	ClusterID string `json:"clusterID"`
+
+	// ProviderSpec contains the cloud provider specific credentials specification. Left as a
+	// RawExtension so each supported cloud can define and evolve its own request type.
+	// +optional
+	ProviderSpec *runtime.RawExtension `json:"providerSpec,omitempty"`
+}
+
+// CredentialsRequestStatus defines the observed state of CredentialsRequest
+type CredentialsRequestStatus struct {
+	// Provisioned is true once the credentials have been minted and stored in the Secret
+	// referenced by SecretRef.
+	Provisioned bool `json:"provisioned"`
+
+	// ProviderStatus contains cloud provider specific status for the credentials. Left as a
+	// RawExtension so each supported cloud can define and evolve its own status type.
+	// +optional
+	ProviderStatus *runtime.RawExtension `json:"providerStatus,omitempty"`
+
+	// Conditions includes detailed status for the CredentialsRequest
+	// +optional
+	Conditions []CredentialsRequestCondition `json:"conditions,omitempty"`
+}
+
+// CredentialsRequestConditionType is a valid value for CredentialsRequestCondition.Type
+type CredentialsRequestConditionType string
+
+const (
+	// InsufficientCloudCredentials is used when the root cloud credentials available to the
+	// operator are not sufficient to satisfy a CredentialsRequest.
+	InsufficientCloudCredentials CredentialsRequestConditionType = "InsufficientCloudCredentials"
+
+	// CredentialsProvisionFailure is used when an attempt to provision credentials for this
+	// request has failed, including when the operator is configured not to provision on its own.
+	CredentialsProvisionFailure CredentialsRequestConditionType = "CredentialsProvisionFailure"
+)
+
+// CredentialsRequestCondition contains details for any of the conditions on a CredentialsRequest
+type CredentialsRequestCondition struct {
+	// Type is the specific type of the condition
+	Type CredentialsRequestConditionType `json:"type"`
+	// Status is the status of the condition
+	Status corev1.ConditionStatus `json:"status"`
+	// LastProbeTime is the last time we probed the condition
+	// +optional
+	LastProbeTime metav1.Time `json:"lastProbeTime,omitempty"`
+	// LastTransitionTime is the last time the condition transitioned from one status to another.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a unique, one-word, CamelCase reason for the condition's last transition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable message indicating details about the last transition
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CredentialsRequest is the Schema for the credentialsrequests API
+type CredentialsRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CredentialsRequestSpec   `json:"spec,omitempty"`
+	Status CredentialsRequestStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CredentialsRequestList contains a list of CredentialsRequest
+type CredentialsRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CredentialsRequest `json:"items"`
+}
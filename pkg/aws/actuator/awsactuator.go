@@ -0,0 +1,917 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actuator
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	minterv1 "github.com/openshift/cred-minter/pkg/apis/credminter/v1beta1"
+	minteraws "github.com/openshift/cred-minter/pkg/aws"
+)
+
+const (
+	rootAWSCredsSecretNamespace = "kube-system"
+	rootAWSCredsSecretName      = "aws-creds"
+
+	awsAccessKeyIDSecretKey     = "aws_access_key_id"
+	awsSecretAccessKeySecretKey = "aws_secret_access_key"
+
+	// maxAccessKeysPerUser is the AWS imposed limit on the number of access keys a single IAM
+	// user may have provisioned at any given time.
+	maxAccessKeysPerUser = 2
+
+	awsSessionTokenSecretKey = "aws_session_token"
+
+	// refreshWindow is how far ahead of an AssumeRole session's expiration we rotate it, so
+	// consumers never observe credentials that are about to stop working.
+	refreshWindow = 30 * time.Minute
+
+	// defaultSTSSessionDuration is used when an AWSSTSProviderSpec doesn't specify one.
+	defaultSTSSessionDuration = int64(time.Hour / time.Second)
+)
+
+// AWSActuator implements the CredentialsRequest Actuator interface for AWS.
+type AWSActuator struct {
+	Client client.Client
+	Codec  *minterv1.Codec
+	Scheme *runtime.Scheme
+
+	// AWSClientBuilder is used to build an AWS client from the access key ID and secret access
+	// key found in an AWS credentials Secret. Overridden by tests.
+	AWSClientBuilder func(accessKeyID, secretAccessKey []byte) (minteraws.Client, error)
+}
+
+// NewAWSActuator creates a new AWSActuator backed by the real AWS client builder.
+func NewAWSActuator(c client.Client, scheme *runtime.Scheme) (*AWSActuator, error) {
+	codec, err := minterv1.NewCodec()
+	if err != nil {
+		return nil, err
+	}
+	return &AWSActuator{
+		Client:           c,
+		Codec:            codec,
+		Scheme:           scheme,
+		AWSClientBuilder: minteraws.NewClient,
+	}, nil
+}
+
+// CanHandle returns true if providerSpec is an AWSProviderSpec or AWSSTSProviderSpec.
+func (a *AWSActuator) CanHandle(providerSpec *runtime.RawExtension) bool {
+	kind, err := a.Codec.ProviderSpecKind(providerSpec)
+	if err != nil {
+		return false
+	}
+	return kind == reflect.TypeOf(minterv1.AWSProviderSpec{}).Name() ||
+		kind == reflect.TypeOf(minterv1.AWSSTSProviderSpec{}).Name()
+}
+
+// Exists returns true if the credentials described by cr have already been provisioned. In
+// Disabled mode, and in Passthrough mode for non-STS provider specs, it always returns true so
+// that cr is routed to Update.
+func (a *AWSActuator) Exists(ctx context.Context, cr *minterv1.CredentialsRequest) (bool, error) {
+	mode, err := a.mode(ctx)
+	if err != nil {
+		return false, err
+	}
+	if mode == minterv1.DisabledCredentialsMode {
+		// Disabled stops all credential issuance, including the AssumeRole path below: there's
+		// nothing to create or update.
+		return true, nil
+	}
+
+	isSTS, err := a.isSTSProviderSpec(cr)
+	if err != nil {
+		return false, err
+	}
+	if isSTS {
+		return a.existsSTS(cr)
+	}
+
+	if mode != minterv1.MintCredentialsMode {
+		// Passthrough has no create/update distinction: it's simply reconciled to the desired
+		// state every time.
+		return true, nil
+	}
+
+	awsStatus, err := a.decodeStatus(cr)
+	if err != nil {
+		return false, err
+	}
+	if awsStatus.User == "" {
+		return false, nil
+	}
+
+	awsClient, err := a.buildAWSClient(cr)
+	if err != nil {
+		return false, err
+	}
+	_, err = awsClient.GetUser(&iam.GetUserInput{UserName: aws.String(awsStatus.User)})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// existsSTS returns true if cr's target Secret holds an AssumeRole session that isn't yet due
+// for rotation.
+func (a *AWSActuator) existsSTS(cr *minterv1.CredentialsRequest) (bool, error) {
+	awsStatus, err := a.decodeStatus(cr)
+	if err != nil {
+		return false, err
+	}
+	window, err := a.stsRefreshWindow(cr)
+	if err != nil {
+		return false, err
+	}
+	return stsSessionValid(awsStatus, window), nil
+}
+
+// stsSessionValid returns true if status records an AssumeRole session that isn't within window
+// of expiring.
+func stsSessionValid(status *minterv1.AWSProviderStatus, window time.Duration) bool {
+	if status.Expiration == nil {
+		return false
+	}
+	return time.Now().Add(window).Before(status.Expiration.Time)
+}
+
+// stsRefreshWindow returns how far ahead of expiration cr's AssumeRole session should be
+// rotated: refreshWindow, or half of the session's own SessionDuration when that is shorter (any
+// session under one hour), so that a short session isn't due for rotation as soon as it's issued.
+func (a *AWSActuator) stsRefreshWindow(cr *minterv1.CredentialsRequest) (time.Duration, error) {
+	stsSpec, err := a.decodeSTSSpec(cr)
+	if err != nil {
+		return 0, err
+	}
+	sessionDuration := stsSpec.SessionDuration
+	if sessionDuration == 0 {
+		sessionDuration = defaultSTSSessionDuration
+	}
+	if half := time.Duration(sessionDuration) * time.Second / 2; half < refreshWindow {
+		return half, nil
+	}
+	return refreshWindow, nil
+}
+
+func (a *AWSActuator) decodeSTSSpec(cr *minterv1.CredentialsRequest) (*minterv1.AWSSTSProviderSpec, error) {
+	stsSpec := &minterv1.AWSSTSProviderSpec{}
+	if err := a.Codec.DecodeProviderSpec(cr.Spec.ProviderSpec, stsSpec); err != nil {
+		return nil, fmt.Errorf("error decoding AWS STS provider spec: %v", err)
+	}
+	return stsSpec, nil
+}
+
+// Create creates the credentials described by cr. It returns whether credentials were actually
+// minted/copied into cr's target Secret, which is false (with no error) when Disabled mode or a
+// denied Passthrough permissions check intentionally left cr unprovisioned.
+func (a *AWSActuator) Create(ctx context.Context, cr *minterv1.CredentialsRequest) (bool, error) {
+	return a.sync(ctx, cr, true)
+}
+
+// Update reconciles the credentials described by cr against their current state in AWS. See
+// Create for the meaning of its returned bool.
+func (a *AWSActuator) Update(ctx context.Context, cr *minterv1.CredentialsRequest) (bool, error) {
+	return a.sync(ctx, cr, false)
+}
+
+// Delete removes any credentials previously provisioned for cr. It decides what to clean up from
+// awsStatus.User rather than the operator's current mode: a CredentialsRequest minted under Mint
+// mode and later left alone after a switch to Disabled or Passthrough still has a real IAM user
+// that needs deleting, regardless of what mode reconciles it now.
+func (a *AWSActuator) Delete(ctx context.Context, cr *minterv1.CredentialsRequest) error {
+	isSTS, err := a.isSTSProviderSpec(cr)
+	if err != nil {
+		return err
+	}
+	if isSTS {
+		// AssumeRole sessions aren't a standing AWS resource, there's nothing to clean up
+		// beyond the target Secret, which garbage collection already handles.
+		return nil
+	}
+
+	awsStatus, err := a.decodeStatus(cr)
+	if err != nil {
+		return err
+	}
+	if awsStatus.User == "" {
+		// Nothing was ever minted in AWS for cr (e.g. it's only ever been reconciled in
+		// Disabled or Passthrough mode), just the target Secret, which garbage collection of
+		// the CredentialsRequest's owned objects already handles.
+		return nil
+	}
+
+	awsClient, err := a.buildAWSClient(cr)
+	if err != nil {
+		return err
+	}
+
+	keysOutput, err := awsClient.ListAccessKeys(&iam.ListAccessKeysInput{UserName: aws.String(awsStatus.User)})
+	if err != nil {
+		return err
+	}
+	for _, key := range keysOutput.AccessKeyMetadata {
+		if _, err := awsClient.DeleteAccessKey(&iam.DeleteAccessKeyInput{
+			UserName:    aws.String(awsStatus.User),
+			AccessKeyId: key.AccessKeyId,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := awsClient.DeleteUserPolicy(&iam.DeleteUserPolicyInput{
+		UserName:   aws.String(awsStatus.User),
+		PolicyName: aws.String(policyNameForUser(awsStatus.User)),
+	}); err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
+			return err
+		}
+	}
+
+	if _, err := awsClient.DeleteUser(&iam.DeleteUserInput{UserName: aws.String(awsStatus.User)}); err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sync dispatches cr to the sync path for the operator's configured mode (or AssumeRole, STS
+// ProviderSpecs take precedence over mode), returning whether credentials were actually
+// minted/copied into cr's target Secret. isNew is true when cr's backing AWS resources (IAM user,
+// in Mint mode) don't exist yet and must be created.
+func (a *AWSActuator) sync(ctx context.Context, cr *minterv1.CredentialsRequest, isNew bool) (bool, error) {
+	mode, err := a.mode(ctx)
+	if err != nil {
+		return false, err
+	}
+	if mode == minterv1.DisabledCredentialsMode {
+		return false, a.syncDisabled(ctx, cr)
+	}
+
+	isSTS, err := a.isSTSProviderSpec(cr)
+	if err != nil {
+		return false, err
+	}
+	if isSTS {
+		return true, a.syncSTS(ctx, cr)
+	}
+
+	switch mode {
+	case minterv1.PassthroughCredentialsMode:
+		return a.syncPassthrough(ctx, cr)
+	default:
+		return true, a.syncMint(ctx, cr, isNew)
+	}
+}
+
+func (a *AWSActuator) syncMint(ctx context.Context, cr *minterv1.CredentialsRequest, isNew bool) error {
+	awsSpec, err := a.decodeSpec(cr)
+	if err != nil {
+		return err
+	}
+	awsStatus, err := a.decodeStatus(cr)
+	if err != nil {
+		return err
+	}
+
+	userName := awsStatus.User
+	if userName == "" {
+		userName = generateUserName(cr)
+	}
+
+	awsClient, err := a.buildAWSClient(cr)
+	if err != nil {
+		return err
+	}
+
+	if isNew {
+		if _, err := awsClient.CreateUser(&iam.CreateUserInput{UserName: aws.String(userName)}); err != nil {
+			return fmt.Errorf("error creating IAM user: %v", err)
+		}
+	}
+
+	if err := a.syncUserPolicy(awsClient, userName, awsSpec.StatementEntries); err != nil {
+		return err
+	}
+
+	if err := a.syncAccessKey(cr, awsClient, userName); err != nil {
+		return err
+	}
+
+	awsStatus.User = userName
+	return a.updateProviderStatus(ctx, cr, awsStatus)
+}
+
+// syncUserPolicy reconciles userName's inline policy against the desired statement entries,
+// only calling PutUserPolicy when the policy currently attached to the user (if any) has
+// drifted from what's desired.
+func (a *AWSActuator) syncUserPolicy(awsClient minteraws.Client, userName string, statements []minterv1.StatementEntry) error {
+	desiredDoc := buildPolicyDocument(statements)
+
+	policyName := policyNameForUser(userName)
+	getOutput, err := awsClient.GetUserPolicy(&iam.GetUserPolicyInput{
+		UserName:   aws.String(userName),
+		PolicyName: aws.String(policyName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
+			return fmt.Errorf("error getting user policy: %v", err)
+		}
+	} else {
+		existingDoc, err := decodePolicyDocument(aws.StringValue(getOutput.PolicyDocument))
+		if err != nil {
+			return fmt.Errorf("error decoding existing policy document: %v", err)
+		}
+		if policyDocumentsEqual(existingDoc, desiredDoc) {
+			return nil
+		}
+	}
+
+	policyJSON, err := json.Marshal(desiredDoc)
+	if err != nil {
+		return fmt.Errorf("error building policy document: %v", err)
+	}
+	if _, err := awsClient.PutUserPolicy(&iam.PutUserPolicyInput{
+		UserName:       aws.String(userName),
+		PolicyName:     aws.String(policyName),
+		PolicyDocument: aws.String(string(policyJSON)),
+	}); err != nil {
+		return fmt.Errorf("error putting user policy: %v", err)
+	}
+	return nil
+}
+
+// syncAccessKey ensures the target Secret holds a currently valid access key for userName,
+// rotating it when the key recorded in the Secret is missing or unknown to AWS.
+func (a *AWSActuator) syncAccessKey(cr *minterv1.CredentialsRequest, awsClient minteraws.Client, userName string) error {
+	existingSecret, err := a.getTargetSecret(cr)
+	if err != nil {
+		return err
+	}
+
+	keysOutput, err := awsClient.ListAccessKeys(&iam.ListAccessKeysInput{UserName: aws.String(userName)})
+	if err != nil {
+		return fmt.Errorf("error listing access keys: %v", err)
+	}
+
+	localKeyID := ""
+	if existingSecret != nil {
+		decoded, err := base64.StdEncoding.DecodeString(string(existingSecret.Data[awsAccessKeyIDSecretKey]))
+		if err != nil {
+			return fmt.Errorf("error decoding existing access key ID: %v", err)
+		}
+		localKeyID = string(decoded)
+	}
+	for _, key := range keysOutput.AccessKeyMetadata {
+		if aws.StringValue(key.AccessKeyId) == localKeyID {
+			// Our Secret already has a valid, known-to-AWS access key. Nothing to do.
+			return nil
+		}
+	}
+
+	// The Secret's access key (if any) is stale or missing. Clear out any access keys AWS
+	// still has for this user so we stay under the per-user limit, then mint a fresh one.
+	for _, key := range keysOutput.AccessKeyMetadata {
+		if _, err := awsClient.DeleteAccessKey(&iam.DeleteAccessKeyInput{
+			UserName:    aws.String(userName),
+			AccessKeyId: key.AccessKeyId,
+		}); err != nil {
+			return fmt.Errorf("error deleting stale access key: %v", err)
+		}
+	}
+
+	createOutput, err := awsClient.CreateAccessKey(&iam.CreateAccessKeyInput{UserName: aws.String(userName)})
+	if err != nil {
+		return fmt.Errorf("error creating access key: %v", err)
+	}
+
+	return a.writeTargetSecret(cr, map[string]string{
+		awsAccessKeyIDSecretKey:     aws.StringValue(createOutput.AccessKey.AccessKeyId),
+		awsSecretAccessKeySecretKey: aws.StringValue(createOutput.AccessKey.SecretAccessKey),
+	})
+}
+
+// syncSTS populates cr's target Secret with a short-lived session obtained via sts:AssumeRole,
+// recording the session's expiration so Exists and RequeueAfter know when it needs rotating.
+func (a *AWSActuator) syncSTS(ctx context.Context, cr *minterv1.CredentialsRequest) error {
+	stsSpec, err := a.decodeSTSSpec(cr)
+	if err != nil {
+		return err
+	}
+
+	awsStatus, err := a.decodeStatus(cr)
+	if err != nil {
+		return err
+	}
+	window, err := a.stsRefreshWindow(cr)
+	if err != nil {
+		return err
+	}
+	if stsSessionValid(awsStatus, window) {
+		// Update() can be called with an already-valid session (e.g. a reconcile triggered by
+		// an unrelated change to cr), nothing to rotate yet.
+		return nil
+	}
+
+	rootSecret, err := a.getRootAWSCredsSecret(ctx)
+	if err != nil {
+		return err
+	}
+	rootClient, err := a.buildAWSClientFromSecret(rootSecret)
+	if err != nil {
+		return err
+	}
+
+	sessionDuration := stsSpec.SessionDuration
+	if sessionDuration == 0 {
+		sessionDuration = defaultSTSSessionDuration
+	}
+	assumeRoleInput := &sts.AssumeRoleInput{
+		RoleArn:         aws.String(stsSpec.RoleARN),
+		RoleSessionName: aws.String(generateUserName(cr)),
+		DurationSeconds: aws.Int64(sessionDuration),
+	}
+	if stsSpec.ExternalID != "" {
+		assumeRoleInput.ExternalId = aws.String(stsSpec.ExternalID)
+	}
+	if len(stsSpec.StatementEntries) > 0 {
+		policyJSON, err := json.Marshal(buildPolicyDocument(stsSpec.StatementEntries))
+		if err != nil {
+			return fmt.Errorf("error building session policy: %v", err)
+		}
+		assumeRoleInput.Policy = aws.String(string(policyJSON))
+	}
+
+	assumeRoleOutput, err := rootClient.AssumeRole(assumeRoleInput)
+	if err != nil {
+		return fmt.Errorf("error assuming role %s: %v", stsSpec.RoleARN, err)
+	}
+
+	if err := a.writeTargetSecret(cr, map[string]string{
+		awsAccessKeyIDSecretKey:     aws.StringValue(assumeRoleOutput.Credentials.AccessKeyId),
+		awsSecretAccessKeySecretKey: aws.StringValue(assumeRoleOutput.Credentials.SecretAccessKey),
+		awsSessionTokenSecretKey:    aws.StringValue(assumeRoleOutput.Credentials.SessionToken),
+	}); err != nil {
+		return err
+	}
+
+	expiration := metav1.NewTime(aws.TimeValue(assumeRoleOutput.Credentials.Expiration))
+	return a.updateProviderStatus(ctx, cr, &minterv1.AWSProviderStatus{Expiration: &expiration})
+}
+
+// isSTSProviderSpec returns true if cr's ProviderSpec is an AWSSTSProviderSpec rather than the
+// IAM-user-oriented AWSProviderSpec.
+func (a *AWSActuator) isSTSProviderSpec(cr *minterv1.CredentialsRequest) (bool, error) {
+	kind, err := a.Codec.ProviderSpecKind(cr.Spec.ProviderSpec)
+	if err != nil {
+		return false, fmt.Errorf("error determining provider spec kind: %v", err)
+	}
+	return kind == reflect.TypeOf(minterv1.AWSSTSProviderSpec{}).Name(), nil
+}
+
+// RequeueAfter returns how long the controller should wait before reconciling cr again on a
+// timer. AssumeRole sessions need to be refreshed before they expire; every other credential type
+// has no natural expiration, so it returns zero.
+func (a *AWSActuator) RequeueAfter(ctx context.Context, cr *minterv1.CredentialsRequest) (time.Duration, error) {
+	isSTS, err := a.isSTSProviderSpec(cr)
+	if err != nil || !isSTS {
+		return 0, err
+	}
+
+	mode, err := a.mode(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if mode == minterv1.DisabledCredentialsMode {
+		// Nothing was provisioned, so there's nothing to rotate on a timer.
+		return 0, nil
+	}
+
+	awsStatus, err := a.decodeStatus(cr)
+	if err != nil {
+		return 0, err
+	}
+	if awsStatus.Expiration == nil {
+		return 0, nil
+	}
+
+	window, err := a.stsRefreshWindow(cr)
+	if err != nil {
+		return 0, err
+	}
+	if d := time.Until(awsStatus.Expiration.Time.Add(-window)); d > 0 {
+		return d, nil
+	}
+	// Already within (or past) the refresh window, e.g. the reconcile that should have rotated
+	// the session ran late. Requeue immediately rather than never, so the session still gets
+	// rotated.
+	return time.Second, nil
+}
+
+// syncPassthrough copies the root AWS credentials into cr's target Secret, after verifying via
+// SimulatePrincipalPolicy that the root principal actually has the requested permissions. It
+// returns false, with no error, if that check denies the request: cr is left unprovisioned and an
+// InsufficientCloudCredentials condition records why.
+func (a *AWSActuator) syncPassthrough(ctx context.Context, cr *minterv1.CredentialsRequest) (bool, error) {
+	awsSpec, err := a.decodeSpec(cr)
+	if err != nil {
+		return false, err
+	}
+
+	rootSecret, err := a.getRootAWSCredsSecret(ctx)
+	if err != nil {
+		return false, err
+	}
+	rootClient, err := a.buildAWSClientFromSecret(rootSecret)
+	if err != nil {
+		return false, err
+	}
+
+	actionNames := []*string{}
+	for _, statement := range awsSpec.StatementEntries {
+		for _, action := range statement.Action {
+			actionNames = append(actionNames, aws.String(action))
+		}
+	}
+
+	principalARN, err := rootPrincipalARN(rootClient)
+	if err != nil {
+		return false, fmt.Errorf("error determining root principal ARN: %v", err)
+	}
+	simResult, err := rootClient.SimulatePrincipalPolicy(&iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalARN),
+		ActionNames:     actionNames,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error simulating root principal policy: %v", err)
+	}
+	for _, result := range simResult.EvaluationResults {
+		if aws.StringValue(result.EvalDecision) != "allowed" {
+			return false, a.setCondition(ctx, cr, minterv1.InsufficientCloudCredentials,
+				fmt.Sprintf("root credentials do not allow %s", aws.StringValue(result.EvalActionName)))
+		}
+	}
+
+	rootAccessKeyID, err := decodeSecretValue(rootSecret, awsAccessKeyIDSecretKey)
+	if err != nil {
+		return false, err
+	}
+	rootSecretAccessKey, err := decodeSecretValue(rootSecret, awsSecretAccessKeySecretKey)
+	if err != nil {
+		return false, err
+	}
+	if err := a.writeTargetSecret(cr, map[string]string{
+		awsAccessKeyIDSecretKey:     rootAccessKeyID,
+		awsSecretAccessKeySecretKey: rootSecretAccessKey,
+	}); err != nil {
+		return false, err
+	}
+	if err := a.updateProviderStatus(ctx, cr, &minterv1.AWSProviderStatus{}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// syncDisabled leaves the CredentialsRequest unprovisioned, but records a condition so that
+// anything waiting on its target Secret knows it must be provisioned some other way.
+func (a *AWSActuator) syncDisabled(ctx context.Context, cr *minterv1.CredentialsRequest) error {
+	log.WithField("cr", fmt.Sprintf("%s/%s", cr.Namespace, cr.Name)).
+		Debug("operator is in disabled mode, not provisioning credentials")
+	return a.setCondition(ctx, cr, minterv1.CredentialsProvisionFailure,
+		"the cloud credentials operator is in Disabled mode")
+}
+
+func (a *AWSActuator) setCondition(ctx context.Context, cr *minterv1.CredentialsRequest, condType minterv1.CredentialsRequestConditionType, message string) error {
+	now := metav1.Now()
+	for i, existing := range cr.Status.Conditions {
+		if existing.Type == condType {
+			cr.Status.Conditions[i].Status = corev1.ConditionTrue
+			cr.Status.Conditions[i].Message = message
+			cr.Status.Conditions[i].LastProbeTime = now
+			return a.Client.Status().Update(ctx, cr)
+		}
+	}
+	cr.Status.Conditions = append(cr.Status.Conditions, minterv1.CredentialsRequestCondition{
+		Type:               condType,
+		Status:             corev1.ConditionTrue,
+		Message:            message,
+		LastProbeTime:      now,
+		LastTransitionTime: now,
+	})
+	return a.Client.Status().Update(ctx, cr)
+}
+
+// mode resolves the operator's configured credentials mode. It returns Mint when the
+// cluster-scoped CloudCredential config does not exist (pre-upgrade clusters keep the legacy Mint
+// behavior), and auto-detects Mint vs Passthrough when it exists with an empty CredentialsMode.
+func (a *AWSActuator) mode(ctx context.Context) (minterv1.CredentialsMode, error) {
+	config := &minterv1.CloudCredential{}
+	err := a.Client.Get(ctx, client.ObjectKey{Name: minterv1.CloudCredentialOperatorConfigName}, config)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return minterv1.MintCredentialsMode, nil
+		}
+		return "", err
+	}
+
+	if config.Spec.CredentialsMode != minterv1.CredentialsModeDefault {
+		return config.Spec.CredentialsMode, nil
+	}
+	return a.detectMode(ctx)
+}
+
+// detectMode chooses Mint when the root AWS credentials can create IAM users, Passthrough
+// otherwise.
+func (a *AWSActuator) detectMode(ctx context.Context) (minterv1.CredentialsMode, error) {
+	rootSecret, err := a.getRootAWSCredsSecret(ctx)
+	if err != nil {
+		return "", err
+	}
+	rootClient, err := a.buildAWSClientFromSecret(rootSecret)
+	if err != nil {
+		return "", err
+	}
+
+	principalARN, err := rootPrincipalARN(rootClient)
+	if err != nil {
+		return "", fmt.Errorf("error determining root principal ARN: %v", err)
+	}
+	result, err := rootClient.SimulatePrincipalPolicy(&iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalARN),
+		ActionNames:     []*string{aws.String("iam:CreateUser")},
+	})
+	if err != nil {
+		return "", fmt.Errorf("error detecting credentials mode: %v", err)
+	}
+	for _, r := range result.EvaluationResults {
+		if aws.StringValue(r.EvalDecision) == "allowed" {
+			return minterv1.MintCredentialsMode, nil
+		}
+	}
+	return minterv1.PassthroughCredentialsMode, nil
+}
+
+func (a *AWSActuator) buildAWSClient(cr *minterv1.CredentialsRequest) (minteraws.Client, error) {
+	secret, err := a.getRootAWSCredsSecret(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	return a.buildAWSClientFromSecret(secret)
+}
+
+func (a *AWSActuator) buildAWSClientFromSecret(secret *corev1.Secret) (minteraws.Client, error) {
+	accessKeyID, err := decodeSecretValue(secret, awsAccessKeyIDSecretKey)
+	if err != nil {
+		return nil, err
+	}
+	secretAccessKey, err := decodeSecretValue(secret, awsSecretAccessKeySecretKey)
+	if err != nil {
+		return nil, err
+	}
+	return a.AWSClientBuilder([]byte(accessKeyID), []byte(secretAccessKey))
+}
+
+// decodeSecretValue reads a key from a Secret whose Data values are base64-encoded text (as
+// opposed to the usual convention of Data already holding raw bytes), as is the case for the
+// AWS credentials Secrets this operator reads and writes.
+func decodeSecretValue(secret *corev1.Secret, key string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(string(secret.Data[key]))
+	if err != nil {
+		return "", fmt.Errorf("error decoding secret key %q: %v", key, err)
+	}
+	return string(decoded), nil
+}
+
+func (a *AWSActuator) getRootAWSCredsSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := a.Client.Get(ctx, client.ObjectKey{
+		Namespace: rootAWSCredsSecretNamespace,
+		Name:      rootAWSCredsSecretName,
+	}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("error getting root AWS creds secret: %v", err)
+	}
+	return secret, nil
+}
+
+func (a *AWSActuator) getTargetSecret(cr *minterv1.CredentialsRequest) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := a.Client.Get(context.TODO(), client.ObjectKey{
+		Namespace: cr.Spec.SecretRef.Namespace,
+		Name:      cr.Spec.SecretRef.Name,
+	}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+func (a *AWSActuator) writeTargetSecret(cr *minterv1.CredentialsRequest, data map[string]string) error {
+	existingSecret, err := a.getTargetSecret(cr)
+	if err != nil {
+		return err
+	}
+
+	secretData := map[string][]byte{}
+	for k, v := range data {
+		secretData[k] = []byte(base64.StdEncoding.EncodeToString([]byte(v)))
+	}
+
+	annotationValue := fmt.Sprintf("%s/%s", cr.Namespace, cr.Name)
+
+	if existingSecret == nil {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cr.Spec.SecretRef.Name,
+				Namespace: cr.Spec.SecretRef.Namespace,
+				Annotations: map[string]string{
+					minterv1.AnnotationCredentialsRequest: annotationValue,
+				},
+			},
+			Data: secretData,
+		}
+		return a.Client.Create(context.TODO(), secret)
+	}
+
+	if existingSecret.Annotations == nil {
+		existingSecret.Annotations = map[string]string{}
+	}
+	existingSecret.Annotations[minterv1.AnnotationCredentialsRequest] = annotationValue
+	existingSecret.Data = secretData
+	return a.Client.Update(context.TODO(), existingSecret)
+}
+
+func (a *AWSActuator) decodeSpec(cr *minterv1.CredentialsRequest) (*minterv1.AWSProviderSpec, error) {
+	awsSpec := &minterv1.AWSProviderSpec{}
+	if err := a.Codec.DecodeProviderSpec(cr.Spec.ProviderSpec, awsSpec); err != nil {
+		return nil, fmt.Errorf("error decoding AWS provider spec: %v", err)
+	}
+	return awsSpec, nil
+}
+
+func (a *AWSActuator) decodeStatus(cr *minterv1.CredentialsRequest) (*minterv1.AWSProviderStatus, error) {
+	awsStatus := &minterv1.AWSProviderStatus{}
+	if err := a.Codec.DecodeProviderStatus(cr.Status.ProviderStatus, awsStatus); err != nil {
+		return nil, fmt.Errorf("error decoding AWS provider status: %v", err)
+	}
+	return awsStatus, nil
+}
+
+// updateProviderStatus encodes awsStatus into cr.Status.ProviderStatus and persists it if that
+// changed anything. This must persist on its own rather than relying on the controller's later
+// Status().Update: that update is gated on cr.Status.Provisioned changing, which isn't true on
+// every reconcile that changes ProviderStatus (e.g. an STS session rotating its Expiration while
+// already Provisioned). The unchanged check keeps a steady-state Mint/Passthrough reconcile, which
+// calls this every time regardless of drift, from writing status on every single reconcile.
+func (a *AWSActuator) updateProviderStatus(ctx context.Context, cr *minterv1.CredentialsRequest, awsStatus *minterv1.AWSProviderStatus) error {
+	previous := cr.Status.ProviderStatus
+	encoded, err := a.Codec.EncodeProviderStatus(awsStatus)
+	if err != nil {
+		return fmt.Errorf("error encoding AWS provider status: %v", err)
+	}
+	if previous != nil && bytes.Equal(previous.Raw, encoded.Raw) {
+		return nil
+	}
+	cr.Status.ProviderStatus = encoded
+	return a.Client.Status().Update(ctx, cr)
+}
+
+// policyDocument mirrors the shape of an IAM policy document, see:
+// https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_elements.html
+type policyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []policyStatement `json:"Statement"`
+}
+
+type policyStatement struct {
+	Effect    string                      `json:"Effect"`
+	Action    []string                    `json:"Action"`
+	Resource  string                      `json:"Resource"`
+	Condition minterv1.IAMPolicyCondition `json:"Condition,omitempty"`
+}
+
+func buildPolicyDocument(statements []minterv1.StatementEntry) policyDocument {
+	doc := policyDocument{
+		Version:   "2012-10-17",
+		Statement: make([]policyStatement, 0, len(statements)),
+	}
+	for _, s := range statements {
+		doc.Statement = append(doc.Statement, policyStatement{
+			Effect:    s.Effect,
+			Action:    s.Action,
+			Resource:  s.Resource,
+			Condition: s.PolicyCondition,
+		})
+	}
+	return doc
+}
+
+// decodePolicyDocument parses a policy document as returned by GetUserPolicy, which AWS
+// URL-encodes in its responses.
+func decodePolicyDocument(raw string) (policyDocument, error) {
+	var doc policyDocument
+	decoded, err := url.QueryUnescape(raw)
+	if err != nil {
+		return doc, err
+	}
+	if err := json.Unmarshal([]byte(decoded), &doc); err != nil {
+		return doc, err
+	}
+	return doc, nil
+}
+
+// policyDocumentsEqual compares two policy documents for semantic equality. Statements are
+// compared positionally: since both documents are built from the same StatementEntries ordering
+// (one decoded from IAM, one freshly built from the CredentialsRequest spec), this is sufficient
+// and avoids the cost of matching statements up by content.
+func policyDocumentsEqual(a, b policyDocument) bool {
+	if a.Version != b.Version || len(a.Statement) != len(b.Statement) {
+		return false
+	}
+	for i := range a.Statement {
+		if !policyStatementsEqual(a.Statement[i], b.Statement[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func policyStatementsEqual(a, b policyStatement) bool {
+	if a.Effect != b.Effect || a.Resource != b.Resource {
+		return false
+	}
+	if !stringSlicesEqual(a.Action, b.Action) {
+		return false
+	}
+	return reflect.DeepEqual(a.Condition, b.Condition)
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func policyNameForUser(userName string) string {
+	return fmt.Sprintf("%s-policy", userName)
+}
+
+func generateUserName(cr *minterv1.CredentialsRequest) string {
+	return fmt.Sprintf("%s-%s", cr.Spec.ClusterName, cr.Name)
+}
+
+func rootPrincipalARN(rootClient minteraws.Client) (string, error) {
+	identity, err := rootClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(identity.Arn), nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+)
+
+//go:generate mockgen -source=./client.go -destination=mock/client_generated.go -package=mock
+
+// Client is a wrap around AWS IAM/STS clients that helps with testing.
+type Client interface {
+	CreateAccessKey(*iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error)
+	CreateUser(*iam.CreateUserInput) (*iam.CreateUserOutput, error)
+	DeleteAccessKey(*iam.DeleteAccessKeyInput) (*iam.DeleteAccessKeyOutput, error)
+	DeleteUser(*iam.DeleteUserInput) (*iam.DeleteUserOutput, error)
+	DeleteUserPolicy(*iam.DeleteUserPolicyInput) (*iam.DeleteUserPolicyOutput, error)
+	GetUser(*iam.GetUserInput) (*iam.GetUserOutput, error)
+	GetUserPolicy(*iam.GetUserPolicyInput) (*iam.GetUserPolicyOutput, error)
+	ListAccessKeys(*iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error)
+	PutUserPolicy(*iam.PutUserPolicyInput) (*iam.PutUserPolicyOutput, error)
+	SimulatePrincipalPolicy(*iam.SimulatePrincipalPolicyInput) (*iam.SimulatePrincipalPolicyOutput, error)
+	GetCallerIdentity(*sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error)
+	AssumeRole(*sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error)
+}
+
+type awsClient struct {
+	iamClient iamiface.IAMAPI
+	stsClient stsiface.STSAPI
+}
+
+func (c *awsClient) CreateAccessKey(input *iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error) {
+	return c.iamClient.CreateAccessKey(input)
+}
+
+func (c *awsClient) CreateUser(input *iam.CreateUserInput) (*iam.CreateUserOutput, error) {
+	return c.iamClient.CreateUser(input)
+}
+
+func (c *awsClient) DeleteAccessKey(input *iam.DeleteAccessKeyInput) (*iam.DeleteAccessKeyOutput, error) {
+	return c.iamClient.DeleteAccessKey(input)
+}
+
+func (c *awsClient) DeleteUser(input *iam.DeleteUserInput) (*iam.DeleteUserOutput, error) {
+	return c.iamClient.DeleteUser(input)
+}
+
+func (c *awsClient) DeleteUserPolicy(input *iam.DeleteUserPolicyInput) (*iam.DeleteUserPolicyOutput, error) {
+	return c.iamClient.DeleteUserPolicy(input)
+}
+
+func (c *awsClient) GetUser(input *iam.GetUserInput) (*iam.GetUserOutput, error) {
+	return c.iamClient.GetUser(input)
+}
+
+func (c *awsClient) GetUserPolicy(input *iam.GetUserPolicyInput) (*iam.GetUserPolicyOutput, error) {
+	return c.iamClient.GetUserPolicy(input)
+}
+
+func (c *awsClient) ListAccessKeys(input *iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error) {
+	return c.iamClient.ListAccessKeys(input)
+}
+
+func (c *awsClient) PutUserPolicy(input *iam.PutUserPolicyInput) (*iam.PutUserPolicyOutput, error) {
+	return c.iamClient.PutUserPolicy(input)
+}
+
+func (c *awsClient) SimulatePrincipalPolicy(input *iam.SimulatePrincipalPolicyInput) (*iam.SimulatePrincipalPolicyOutput, error) {
+	return c.iamClient.SimulatePrincipalPolicy(input)
+}
+
+func (c *awsClient) GetCallerIdentity(input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+	return c.stsClient.GetCallerIdentity(input)
+}
+
+func (c *awsClient) AssumeRole(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+	return c.stsClient.AssumeRole(input)
+}
+
+// NewClient creates our client wrapper object for the actual AWS clients we use.
+func NewClient(accessKeyID, secretAccessKey []byte) (Client, error) {
+	awsConfig := &aws.Config{
+		Credentials: credentials.NewStaticCredentials(string(accessKeyID), string(secretAccessKey), ""),
+	}
+	s, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientFromSession(s), nil
+}
+
+// NewClientFromSession wraps an already-configured AWS session. This is split out from NewClient
+// so that tests can point the IAM/STS clients at something other than real AWS (e.g. a custom
+// EndpointResolver backed by an in-process mock server) without reaching into this package's
+// unexported awsClient type.
+func NewClientFromSession(s *session.Session) Client {
+	return &awsClient{
+		iamClient: iam.New(s),
+		stsClient: sts.New(s),
+	}
+}
@@ -0,0 +1,341 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockserver
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type iamUserXML struct {
+	Path       string `xml:"Path"`
+	UserName   string `xml:"UserName"`
+	UserId     string `xml:"UserId"`
+	Arn        string `xml:"Arn"`
+	CreateDate string `xml:"CreateDate"`
+}
+
+func userXML(u *mockUser) iamUserXML {
+	return iamUserXML{
+		Path:       "/",
+		UserName:   u.name,
+		UserId:     u.id,
+		Arn:        u.arn,
+		CreateDate: u.createDate.UTC().Format(awsTimeFormat),
+	}
+}
+
+type createUserResponseXML struct {
+	XMLName          xml.Name `xml:"CreateUserResponse"`
+	CreateUserResult struct {
+		User iamUserXML `xml:"User"`
+	} `xml:"CreateUserResult"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	userName := r.PostForm.Get("UserName")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.users[userName]; exists {
+		s.writeError(w, ErrCodeEntityAlreadyExists, fmt.Sprintf("User with name %s already exists.", userName))
+		return
+	}
+	u := s.newUser(userName)
+	s.users[userName] = u
+
+	resp := createUserResponseXML{ResponseMetadata: responseMetadata{RequestID: s.nextRequestID()}}
+	resp.CreateUserResult.User = userXML(u)
+	writeXML(w, resp)
+}
+
+type getUserResponseXML struct {
+	XMLName       xml.Name `xml:"GetUserResponse"`
+	GetUserResult struct {
+		User iamUserXML `xml:"User"`
+	} `xml:"GetUserResult"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+func (s *Server) handleGetUser(w http.ResponseWriter, r *http.Request) {
+	userName := r.PostForm.Get("UserName")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[userName]
+	if !ok {
+		s.writeError(w, ErrCodeNoSuchEntity, fmt.Sprintf("The user with name %s cannot be found.", userName))
+		return
+	}
+
+	resp := getUserResponseXML{ResponseMetadata: responseMetadata{RequestID: s.nextRequestID()}}
+	resp.GetUserResult.User = userXML(u)
+	writeXML(w, resp)
+}
+
+func (s *Server) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	userName := r.PostForm.Get("UserName")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[userName]; !ok {
+		s.writeError(w, ErrCodeNoSuchEntity, fmt.Sprintf("The user with name %s cannot be found.", userName))
+		return
+	}
+	delete(s.users, userName)
+	s.writeEmptyResult(w, "DeleteUserResponse")
+}
+
+func (s *Server) handlePutUserPolicy(w http.ResponseWriter, r *http.Request) {
+	userName := r.PostForm.Get("UserName")
+	policyName := r.PostForm.Get("PolicyName")
+	policyDocument := r.PostForm.Get("PolicyDocument")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[userName]
+	if !ok {
+		s.writeError(w, ErrCodeNoSuchEntity, fmt.Sprintf("The user with name %s cannot be found.", userName))
+		return
+	}
+	u.policies[policyName] = policyDocument
+	s.writeEmptyResult(w, "PutUserPolicyResponse")
+}
+
+type getUserPolicyResponseXML struct {
+	XMLName             xml.Name `xml:"GetUserPolicyResponse"`
+	GetUserPolicyResult struct {
+		UserName       string `xml:"UserName"`
+		PolicyName     string `xml:"PolicyName"`
+		PolicyDocument string `xml:"PolicyDocument"`
+	} `xml:"GetUserPolicyResult"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+func (s *Server) handleGetUserPolicy(w http.ResponseWriter, r *http.Request) {
+	userName := r.PostForm.Get("UserName")
+	policyName := r.PostForm.Get("PolicyName")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[userName]
+	if !ok {
+		s.writeError(w, ErrCodeNoSuchEntity, fmt.Sprintf("The user with name %s cannot be found.", userName))
+		return
+	}
+	doc, ok := u.policies[policyName]
+	if !ok {
+		s.writeError(w, ErrCodeNoSuchEntity, fmt.Sprintf("The user policy with name %s cannot be found.", policyName))
+		return
+	}
+
+	resp := getUserPolicyResponseXML{ResponseMetadata: responseMetadata{RequestID: s.nextRequestID()}}
+	resp.GetUserPolicyResult.UserName = userName
+	resp.GetUserPolicyResult.PolicyName = policyName
+	// Real IAM URL-encodes the policy document in GetUserPolicy responses; the actuator's
+	// decodePolicyDocument already accounts for this.
+	resp.GetUserPolicyResult.PolicyDocument = url.QueryEscape(doc)
+	writeXML(w, resp)
+}
+
+func (s *Server) handleDeleteUserPolicy(w http.ResponseWriter, r *http.Request) {
+	userName := r.PostForm.Get("UserName")
+	policyName := r.PostForm.Get("PolicyName")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[userName]
+	if !ok {
+		s.writeError(w, ErrCodeNoSuchEntity, fmt.Sprintf("The user with name %s cannot be found.", userName))
+		return
+	}
+	if _, ok := u.policies[policyName]; !ok {
+		s.writeError(w, ErrCodeNoSuchEntity, fmt.Sprintf("The user policy with name %s cannot be found.", policyName))
+		return
+	}
+	delete(u.policies, policyName)
+	s.writeEmptyResult(w, "DeleteUserPolicyResponse")
+}
+
+type iamAccessKeyMetadataXML struct {
+	UserName    string `xml:"UserName"`
+	AccessKeyId string `xml:"AccessKeyId"`
+	Status      string `xml:"Status"`
+	CreateDate  string `xml:"CreateDate"`
+}
+
+type listAccessKeysResponseXML struct {
+	XMLName              xml.Name `xml:"ListAccessKeysResponse"`
+	ListAccessKeysResult struct {
+		UserName          string                    `xml:"UserName"`
+		AccessKeyMetadata []iamAccessKeyMetadataXML `xml:"AccessKeyMetadata>member"`
+		IsTruncated       bool                      `xml:"IsTruncated"`
+	} `xml:"ListAccessKeysResult"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+func (s *Server) handleListAccessKeys(w http.ResponseWriter, r *http.Request) {
+	userName := r.PostForm.Get("UserName")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[userName]
+	if !ok {
+		s.writeError(w, ErrCodeNoSuchEntity, fmt.Sprintf("The user with name %s cannot be found.", userName))
+		return
+	}
+
+	members := make([]iamAccessKeyMetadataXML, 0, len(u.keys))
+	for _, key := range u.keys {
+		members = append(members, iamAccessKeyMetadataXML{
+			UserName:    userName,
+			AccessKeyId: key.id,
+			Status:      key.status,
+			CreateDate:  key.createDate.UTC().Format(awsTimeFormat),
+		})
+	}
+
+	resp := listAccessKeysResponseXML{ResponseMetadata: responseMetadata{RequestID: s.nextRequestID()}}
+	resp.ListAccessKeysResult.UserName = userName
+	resp.ListAccessKeysResult.AccessKeyMetadata = members
+	writeXML(w, resp)
+}
+
+type iamAccessKeyXML struct {
+	UserName        string `xml:"UserName"`
+	AccessKeyId     string `xml:"AccessKeyId"`
+	Status          string `xml:"Status"`
+	SecretAccessKey string `xml:"SecretAccessKey"`
+	CreateDate      string `xml:"CreateDate"`
+}
+
+type createAccessKeyResponseXML struct {
+	XMLName               xml.Name `xml:"CreateAccessKeyResponse"`
+	CreateAccessKeyResult struct {
+		AccessKey iamAccessKeyXML `xml:"AccessKey"`
+	} `xml:"CreateAccessKeyResult"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+func (s *Server) handleCreateAccessKey(w http.ResponseWriter, r *http.Request) {
+	userName := r.PostForm.Get("UserName")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[userName]
+	if !ok {
+		s.writeError(w, ErrCodeNoSuchEntity, fmt.Sprintf("The user with name %s cannot be found.", userName))
+		return
+	}
+	if len(u.keys) >= maxAccessKeysPerUser {
+		s.writeError(w, ErrCodeLimitExceeded,
+			fmt.Sprintf("Cannot exceed quota for AccessKeysPerUser: %d", maxAccessKeysPerUser))
+		return
+	}
+
+	key := s.newAccessKey()
+	u.keys = append(u.keys, key)
+
+	resp := createAccessKeyResponseXML{ResponseMetadata: responseMetadata{RequestID: s.nextRequestID()}}
+	resp.CreateAccessKeyResult.AccessKey = iamAccessKeyXML{
+		UserName:        userName,
+		AccessKeyId:     key.id,
+		Status:          key.status,
+		SecretAccessKey: key.secret,
+		CreateDate:      key.createDate.UTC().Format(awsTimeFormat),
+	}
+	writeXML(w, resp)
+}
+
+func (s *Server) handleDeleteAccessKey(w http.ResponseWriter, r *http.Request) {
+	userName := r.PostForm.Get("UserName")
+	accessKeyID := r.PostForm.Get("AccessKeyId")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[userName]
+	if !ok {
+		s.writeError(w, ErrCodeNoSuchEntity, fmt.Sprintf("The user with name %s cannot be found.", userName))
+		return
+	}
+	idx := -1
+	for i, key := range u.keys {
+		if key.id == accessKeyID {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		s.writeError(w, ErrCodeNoSuchEntity, fmt.Sprintf("The Access Key with id %s cannot be found.", accessKeyID))
+		return
+	}
+	u.keys = append(u.keys[:idx], u.keys[idx+1:]...)
+	s.writeEmptyResult(w, "DeleteAccessKeyResponse")
+}
+
+type iamEvaluationResultXML struct {
+	EvalActionName   string `xml:"EvalActionName"`
+	EvalResourceName string `xml:"EvalResourceName"`
+	EvalDecision     string `xml:"EvalDecision"`
+}
+
+type simulatePrincipalPolicyResponseXML struct {
+	XMLName                       xml.Name `xml:"SimulatePrincipalPolicyResponse"`
+	SimulatePrincipalPolicyResult struct {
+		EvaluationResults []iamEvaluationResultXML `xml:"EvaluationResults>member"`
+		IsTruncated       bool                     `xml:"IsTruncated"`
+	} `xml:"SimulatePrincipalPolicyResult"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+// handleSimulatePrincipalPolicy always reports every requested action as allowed: the mock server
+// has no IAM policy engine to evaluate against. FailNextAction can make the call fail with an error
+// code, but this server never returns a deny decision.
+func (s *Server) handleSimulatePrincipalPolicy(w http.ResponseWriter, r *http.Request) {
+	results := []iamEvaluationResultXML{}
+	for i := 1; ; i++ {
+		action := r.PostForm.Get(fmt.Sprintf("ActionNames.member.%d", i))
+		if action == "" {
+			break
+		}
+		results = append(results, iamEvaluationResultXML{
+			EvalActionName:   action,
+			EvalResourceName: "*",
+			EvalDecision:     "allowed",
+		})
+	}
+
+	resp := simulatePrincipalPolicyResponseXML{ResponseMetadata: responseMetadata{RequestID: s.nextRequestID()}}
+	resp.SimulatePrincipalPolicyResult.EvaluationResults = results
+	writeXML(w, resp)
+}
+
+// writeEmptyResult writes a response with only a ResponseMetadata body, for actions (DeleteUser,
+// PutUserPolicy, DeleteUserPolicy, DeleteAccessKey) whose real API responses carry no result
+// payload.
+func (s *Server) writeEmptyResult(w http.ResponseWriter, responseElement string) {
+	writeXML(w, struct {
+		XMLName          xml.Name
+		ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+	}{
+		XMLName:          xml.Name{Local: responseElement},
+		ResponseMetadata: responseMetadata{RequestID: s.nextRequestID()},
+	})
+}
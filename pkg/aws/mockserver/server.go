@@ -0,0 +1,171 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mockserver stands up an in-process HTTP server that speaks enough of the real IAM/STS
+// query-protocol wire format to back an actual aws-sdk-go client. Unlike pkg/aws/mock's
+// mockgen-generated Client mock, requests here are marshaled/unmarshaled by the real SDK and served by
+// a small model of AWS's own behavior (persistent users, per-user access key limits, realistic
+// error codes), so tests exercise bugs the interface-level mock can't see.
+package mockserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	minteraws "github.com/openshift/cred-minter/pkg/aws"
+)
+
+// Server is an in-process mock of the IAM/STS APIs used by the AWS actuator.
+type Server struct {
+	httpServer *httptest.Server
+
+	mu               sync.Mutex
+	users            map[string]*mockUser
+	userCounter      int
+	accessKeyCounter int
+	requestCounter   int
+
+	// Latency, if non-zero, is slept before every request is handled, to exercise
+	// timeout/context-cancellation paths.
+	Latency time.Duration
+
+	// failNext, keyed by Action, makes the next call to that Action fail with the given error
+	// instead of being handled normally. Consumed after a single use.
+	failNext map[string]mockError
+
+	// handlers maps every IAM/STS Action this server understands to its handler. Built once in
+	// New rather than per-request since the handlers are just methods bound to s.
+	handlers map[string]func(http.ResponseWriter, *http.Request)
+}
+
+// New starts a mock IAM/STS server. Callers must call Close when done with it.
+func New() *Server {
+	s := &Server{
+		users:    map[string]*mockUser{},
+		failNext: map[string]mockError{},
+	}
+	s.httpServer = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.handlers = s.actions()
+	return s
+}
+
+// Close shuts down the underlying HTTP server.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// URL is the address the mock server is listening on.
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// FailNextAction makes the next call to action fail with the given error code/message instead of
+// being handled normally. The injected failure is consumed after one use.
+func (s *Server) FailNextAction(action, code, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failNext[action] = mockError{code: code, message: message}
+}
+
+// takeFailure returns and clears any failure injected for action via FailNextAction.
+func (s *Server) takeFailure(action string) (mockError, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	failure, ok := s.failNext[action]
+	if ok {
+		delete(s.failNext, action)
+	}
+	return failure, ok
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.Latency > 0 {
+		time.Sleep(s.Latency)
+	}
+
+	if err := r.ParseForm(); err != nil {
+		s.writeError(w, "InvalidAction", err.Error())
+		return
+	}
+	action := r.PostForm.Get("Action")
+
+	if failure, ok := s.takeFailure(action); ok {
+		s.writeError(w, failure.code, failure.message)
+		return
+	}
+
+	handler, ok := s.handlers[action]
+	if !ok {
+		s.writeError(w, "InvalidAction", "The action "+action+" is not valid for this web service.")
+		return
+	}
+	handler(w, r)
+}
+
+// actions maps every IAM/STS Action this server understands to its handler.
+func (s *Server) actions() map[string]func(http.ResponseWriter, *http.Request) {
+	return map[string]func(http.ResponseWriter, *http.Request){
+		"CreateUser":              s.handleCreateUser,
+		"GetUser":                 s.handleGetUser,
+		"DeleteUser":              s.handleDeleteUser,
+		"PutUserPolicy":           s.handlePutUserPolicy,
+		"GetUserPolicy":           s.handleGetUserPolicy,
+		"DeleteUserPolicy":        s.handleDeleteUserPolicy,
+		"ListAccessKeys":          s.handleListAccessKeys,
+		"CreateAccessKey":         s.handleCreateAccessKey,
+		"DeleteAccessKey":         s.handleDeleteAccessKey,
+		"SimulatePrincipalPolicy": s.handleSimulatePrincipalPolicy,
+		"GetCallerIdentity":       s.handleGetCallerIdentity,
+		"AssumeRole":              s.handleAssumeRole,
+	}
+}
+
+// Resolver returns an endpoints.Resolver that points the IAM and STS services at this server,
+// falling back to the real AWS partition metadata for anything else.
+func (s *Server) Resolver() endpoints.ResolverFunc {
+	return func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		switch service {
+		case endpoints.IamServiceID, endpoints.StsServiceID:
+			return endpoints.ResolvedEndpoint{
+				URL:           s.URL(),
+				SigningRegion: "us-east-1",
+			}, nil
+		}
+		return endpoints.DefaultResolver().EndpointFor(service, region, opts...)
+	}
+}
+
+// Client builds a minteraws.Client backed by this mock server instead of real AWS.
+func (s *Server) Client() (minteraws.Client, error) {
+	awsConfig := &aws.Config{
+		Credentials:      credentials.NewStaticCredentials("mock-access-key-id", "mock-secret-access-key", ""),
+		Region:           aws.String("us-east-1"),
+		EndpointResolver: s.Resolver(),
+		DisableSSL:       aws.Bool(true),
+	}
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return minteraws.NewClientFromSession(sess), nil
+}
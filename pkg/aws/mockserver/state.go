@@ -0,0 +1,111 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockserver
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxAccessKeysPerUser mirrors the real IAM limit of two access keys per user.
+const maxAccessKeysPerUser = 2
+
+const mockAccountID = "123456789012"
+
+// mockUser is the server's record of an IAM user: its inline policies and access keys.
+type mockUser struct {
+	name       string
+	id         string
+	arn        string
+	createDate time.Time
+	policies   map[string]string
+	keys       []*mockAccessKey
+}
+
+type mockAccessKey struct {
+	id         string
+	secret     string
+	status     string
+	createDate time.Time
+}
+
+func (s *Server) newUser(name string) *mockUser {
+	s.userCounter++
+	return &mockUser{
+		name:       name,
+		id:         fmt.Sprintf("AIDAMOCK%012d", s.userCounter),
+		arn:        fmt.Sprintf("arn:aws:iam::%s:user/%s", mockAccountID, name),
+		createDate: time.Now(),
+		policies:   map[string]string{},
+	}
+}
+
+func (s *Server) newAccessKey() *mockAccessKey {
+	s.accessKeyCounter++
+	return &mockAccessKey{
+		id:         fmt.Sprintf("AKIAMOCK%012d", s.accessKeyCounter),
+		secret:     fmt.Sprintf("mocksecret%024d", s.accessKeyCounter),
+		status:     "Active",
+		createDate: time.Now(),
+	}
+}
+
+// getUser returns the named user, or nil if no such user has been created.
+func (s *Server) getUser(name string) *mockUser {
+	return s.users[name]
+}
+
+// SeedUser registers a user directly, without going through CreateUser, so tests can arrange
+// server state (e.g. a user that already has access keys) before exercising the actuator against
+// it.
+func (s *Server) SeedUser(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.users[name]; exists {
+		return
+	}
+	s.users[name] = s.newUser(name)
+}
+
+// SeedAccessKey adds an access key to the named user, enforcing the same maxAccessKeysPerUser cap
+// CreateAccessKey does, and returns the new key's ID and secret.
+func (s *Server) SeedAccessKey(userName string) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[userName]
+	if !ok {
+		return "", "", fmt.Errorf("mockserver: no such user %s", userName)
+	}
+	if len(u.keys) >= maxAccessKeysPerUser {
+		return "", "", fmt.Errorf("mockserver: user %s already has the maximum of %d access keys", userName, maxAccessKeysPerUser)
+	}
+	key := s.newAccessKey()
+	u.keys = append(u.keys, key)
+	return key.id, key.secret, nil
+}
+
+// AccessKeyCount returns how many access keys the named user currently has, for tests asserting
+// that a rotation did or didn't happen.
+func (s *Server) AccessKeyCount(userName string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[userName]
+	if !ok {
+		return 0
+	}
+	return len(u.keys)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockserver
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// mockRootArn is the identity GetCallerIdentity reports for the root credentials used to reach
+// this server, mirroring the IAM user the actuator's root creds Secret would normally belong to.
+const mockRootArn = "arn:aws:iam::" + mockAccountID + ":user/mock-root"
+
+type getCallerIdentityResponseXML struct {
+	XMLName                 xml.Name `xml:"GetCallerIdentityResponse"`
+	GetCallerIdentityResult struct {
+		Arn     string `xml:"Arn"`
+		UserId  string `xml:"UserId"`
+		Account string `xml:"Account"`
+	} `xml:"GetCallerIdentityResult"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+func (s *Server) handleGetCallerIdentity(w http.ResponseWriter, r *http.Request) {
+	resp := getCallerIdentityResponseXML{ResponseMetadata: responseMetadata{RequestID: s.nextRequestID()}}
+	resp.GetCallerIdentityResult.Arn = mockRootArn
+	resp.GetCallerIdentityResult.UserId = "AIDAMOCKROOT00000000"
+	resp.GetCallerIdentityResult.Account = mockAccountID
+	writeXML(w, resp)
+}
+
+type assumeRoleResponseXML struct {
+	XMLName          xml.Name `xml:"AssumeRoleResponse"`
+	AssumeRoleResult struct {
+		Credentials struct {
+			AccessKeyId     string `xml:"AccessKeyId"`
+			SecretAccessKey string `xml:"SecretAccessKey"`
+			SessionToken    string `xml:"SessionToken"`
+			Expiration      string `xml:"Expiration"`
+		} `xml:"Credentials"`
+		AssumedRoleUser struct {
+			AssumedRoleId string `xml:"AssumedRoleId"`
+			Arn           string `xml:"Arn"`
+		} `xml:"AssumedRoleUser"`
+	} `xml:"AssumeRoleResult"`
+	ResponseMetadata responseMetadata `xml:"ResponseMetadata"`
+}
+
+func (s *Server) handleAssumeRole(w http.ResponseWriter, r *http.Request) {
+	roleArn := r.PostForm.Get("RoleArn")
+	sessionName := r.PostForm.Get("RoleSessionName")
+	durationSeconds := int64(3600)
+	if raw := r.PostForm.Get("DurationSeconds"); raw != "" {
+		fmt.Sscanf(raw, "%d", &durationSeconds)
+	}
+
+	s.mu.Lock()
+	s.accessKeyCounter++
+	counter := s.accessKeyCounter
+	s.mu.Unlock()
+
+	resp := assumeRoleResponseXML{ResponseMetadata: responseMetadata{RequestID: s.nextRequestID()}}
+	resp.AssumeRoleResult.Credentials.AccessKeyId = fmt.Sprintf("ASIAMOCK%012d", counter)
+	resp.AssumeRoleResult.Credentials.SecretAccessKey = fmt.Sprintf("mockstssecret%021d", counter)
+	resp.AssumeRoleResult.Credentials.SessionToken = fmt.Sprintf("mock-session-token-%d", counter)
+	resp.AssumeRoleResult.Credentials.Expiration = time.Now().Add(time.Duration(durationSeconds) * time.Second).UTC().Format(awsTimeFormat)
+	resp.AssumeRoleResult.AssumedRoleUser.AssumedRoleId = fmt.Sprintf("AROAMOCK%012d:%s", counter, sessionName)
+	resp.AssumeRoleResult.AssumedRoleUser.Arn = fmt.Sprintf("arn:aws:sts::%s:assumed-role/%s/%s", mockAccountID, roleNameFromArn(roleArn), sessionName)
+	writeXML(w, resp)
+}
+
+// roleNameFromArn extracts the role name from a role ARN (the part after the last "/"), falling
+// back to the whole ARN if it doesn't look like one.
+func roleNameFromArn(roleArn string) string {
+	for i := len(roleArn) - 1; i >= 0; i-- {
+		if roleArn[i] == '/' {
+			return roleArn[i+1:]
+		}
+	}
+	return roleArn
+}
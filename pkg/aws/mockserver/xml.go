@@ -0,0 +1,103 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockserver
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// awsTimeFormat is the timestamp layout real IAM/STS responses use, shared by both services'
+// handlers so a format change only needs to happen in one place.
+const awsTimeFormat = "2006-01-02T15:04:05Z"
+
+// Error codes the mock server can be asked to return via FailNextAction. These mirror the subset of
+// real IAM/STS error codes the actuator's error handling cares about.
+const (
+	ErrCodeNoSuchEntity        = iam.ErrCodeNoSuchEntityException
+	ErrCodeEntityAlreadyExists = iam.ErrCodeEntityAlreadyExistsException
+	ErrCodeLimitExceeded       = iam.ErrCodeLimitExceededException
+	ErrCodeThrottling          = "Throttling"
+)
+
+// mockError is an injectable failure: the error code/message the server should return the next
+// time a given Action is called, instead of handling it normally.
+type mockError struct {
+	code    string
+	message string
+}
+
+// httpStatusForCode mirrors the HTTP status real IAM/STS return for each error code. The SDK's
+// query protocol unmarshaler keys off the <Code> element, not the status, but a realistic status
+// exercises the same is-this-an-error branch the real client hits.
+func httpStatusForCode(code string) int {
+	switch code {
+	case ErrCodeNoSuchEntity:
+		return http.StatusNotFound
+	case ErrCodeThrottling:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+type xmlErrorResponse struct {
+	XMLName   xml.Name `xml:"ErrorResponse"`
+	Error     xmlError `xml:"Error"`
+	RequestID string   `xml:"RequestId"`
+}
+
+type xmlError struct {
+	Type    string `xml:"Type"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (s *Server) writeError(w http.ResponseWriter, code, message string) {
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(httpStatusForCode(code))
+	writeXML(w, xmlErrorResponse{
+		Error: xmlError{
+			Type:    "Sender",
+			Code:    code,
+			Message: message,
+		},
+		RequestID: s.nextRequestID(),
+	})
+}
+
+func (s *Server) nextRequestID() string {
+	s.requestCounter++
+	return fmt.Sprintf("mock-request-%d", s.requestCounter)
+}
+
+// writeXML marshals v onto w, prefixed with the XML header real AWS responses include. Handlers
+// pass in plain structs; errors marshaling our own hand-built response types would be a mock
+// server bug, not something callers need to handle, so they're not returned.
+func writeXML(w http.ResponseWriter, v interface{}) {
+	fmt.Fprint(w, xml.Header)
+	if err := xml.NewEncoder(w).Encode(v); err != nil {
+		panic(fmt.Sprintf("mockserver: failed to encode response: %v", err))
+	}
+}
+
+type responseMetadata struct {
+	RequestID string `xml:"RequestId"`
+}
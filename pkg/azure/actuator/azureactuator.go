@@ -0,0 +1,488 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actuator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Azure/azure-sdk-for-go/services/authorization/mgmt/2015-07-01/authorization"
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/satori/go.uuid"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	minterv1 "github.com/openshift/cred-minter/pkg/apis/credminter/v1beta1"
+	minterazure "github.com/openshift/cred-minter/pkg/azure"
+)
+
+const (
+	defaultRootCredsSecretNamespace = "kube-system"
+	defaultRootCredsSecretName      = "azure-credentials"
+
+	// azureTenantIDSecretKey, azureClientIDSecretKey, and azureClientSecretSecretKey are used both
+	// in the root creds Secret (identifying the actuator's own credentials) and in each
+	// CredentialsRequest's target Secret (identifying the service principal minted for it).
+	azureTenantIDSecretKey       = "azure_tenant_id"
+	azureClientIDSecretKey       = "azure_client_id"
+	azureClientSecretSecretKey   = "azure_client_secret"
+	azureSubscriptionIDSecretKey = "azure_subscription_id"
+)
+
+// AzureActuator implements the CredentialsRequest Actuator interface for Azure.
+type AzureActuator struct {
+	Client client.Client
+	Codec  *minterv1.Codec
+	Scheme *runtime.Scheme
+
+	// RootCredsSecretNamespace/RootCredsSecretName identify the Secret holding the root Azure
+	// credentials used to create service principals and role assignments for CredentialsRequests.
+	RootCredsSecretNamespace string
+	RootCredsSecretName      string
+
+	// AzureClientBuilder is used to build an Azure client from the root credentials found in
+	// RootCredsSecretNamespace/RootCredsSecretName. Overridden by tests.
+	AzureClientBuilder func(tenantID, subscriptionID, clientID, clientSecret string) (minterazure.Client, error)
+}
+
+// NewAzureActuator creates a new AzureActuator backed by the real Azure client builder, reading
+// root credentials from the default kube-system/azure-credentials Secret.
+func NewAzureActuator(c client.Client, scheme *runtime.Scheme) (*AzureActuator, error) {
+	codec, err := minterv1.NewCodec()
+	if err != nil {
+		return nil, err
+	}
+	return &AzureActuator{
+		Client:                   c,
+		Codec:                    codec,
+		Scheme:                   scheme,
+		RootCredsSecretNamespace: defaultRootCredsSecretNamespace,
+		RootCredsSecretName:      defaultRootCredsSecretName,
+		AzureClientBuilder:       minterazure.NewClient,
+	}, nil
+}
+
+// CanHandle returns true if providerSpec is an AzureProviderSpec.
+func (a *AzureActuator) CanHandle(providerSpec *runtime.RawExtension) bool {
+	kind, err := a.Codec.ProviderSpecKind(providerSpec)
+	if err != nil {
+		return false
+	}
+	return kind == reflect.TypeOf(minterv1.AzureProviderSpec{}).Name()
+}
+
+// Exists returns true if the service principal described by cr has already been provisioned. In
+// any mode other than Mint it always returns true so that cr is routed to Update.
+func (a *AzureActuator) Exists(ctx context.Context, cr *minterv1.CredentialsRequest) (bool, error) {
+	mode, err := a.mode(ctx)
+	if err != nil {
+		return false, err
+	}
+	if mode != minterv1.MintCredentialsMode {
+		// Disabled and Passthrough (unsupported for Azure) never create anything to check for;
+		// routing to Update avoids making a live Azure API call for either.
+		return true, nil
+	}
+
+	azureStatus, err := a.decodeStatus(cr)
+	if err != nil {
+		return false, err
+	}
+	if azureStatus.ObjectID == "" {
+		return false, nil
+	}
+
+	azureClient, err := a.buildAzureClient(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+	if _, err := azureClient.GetApplication(ctx, azureStatus.ObjectID); err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Create creates the service principal and role assignments described by cr, gated on the
+// operator's configured mode. It returns whether credentials were actually minted into cr's
+// target Secret, which is false (with no error) when Disabled or Passthrough mode intentionally
+// left cr unprovisioned.
+func (a *AzureActuator) Create(ctx context.Context, cr *minterv1.CredentialsRequest) (bool, error) {
+	return a.sync(ctx, cr, true)
+}
+
+// Update reconciles cr against its current Azure state. See Create for the meaning of its
+// returned bool.
+func (a *AzureActuator) Update(ctx context.Context, cr *minterv1.CredentialsRequest) (bool, error) {
+	return a.sync(ctx, cr, false)
+}
+
+// sync dispatches cr to the sync path for the operator's configured mode, returning whether
+// credentials were actually minted into cr's target Secret. isNew is true when cr's service
+// principal doesn't exist yet and must be created.
+func (a *AzureActuator) sync(ctx context.Context, cr *minterv1.CredentialsRequest, isNew bool) (bool, error) {
+	mode, err := a.mode(ctx)
+	if err != nil {
+		return false, err
+	}
+	switch mode {
+	case minterv1.DisabledCredentialsMode:
+		return false, a.syncDisabled(ctx, cr)
+	case minterv1.PassthroughCredentialsMode:
+		return false, a.syncPassthroughUnsupported(ctx, cr)
+	default:
+		if !isNew {
+			// Role assignments are established once at Create time. Unlike the AWS actuator's IAM
+			// policy document, an Azure role assignment isn't a single resource that can be
+			// overwritten wholesale. Update doesn't reconcile RoleDefinitions/Scopes: changing an
+			// already-provisioned CredentialsRequest's AzureProviderSpec has no effect, and neither
+			// the role assignments nor the target Secret are rewritten. Only once the AD
+			// application recorded in its status no longer exists does Exists report false and
+			// Create run again.
+			return true, nil
+		}
+		return true, a.syncMint(ctx, cr)
+	}
+}
+
+// syncMint creates the service principal and role assignments described by cr.
+func (a *AzureActuator) syncMint(ctx context.Context, cr *minterv1.CredentialsRequest) error {
+	azureSpec, err := a.decodeSpec(cr)
+	if err != nil {
+		return err
+	}
+
+	azureClient, err := a.buildAzureClient(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	appName := generateServicePrincipalName(cr)
+	clientSecret := uuid.NewV4().String()
+	app, err := azureClient.CreateApplication(ctx, graphrbac.ApplicationCreateParameters{
+		DisplayName:             to.StringPtr(appName),
+		IdentifierUris:          &[]string{fmt.Sprintf("https://%s", appName)},
+		AvailableToOtherTenants: to.BoolPtr(false),
+		PasswordCredentials: &[]graphrbac.PasswordCredential{
+			{Value: to.StringPtr(clientSecret)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating AD application: %v", err)
+	}
+
+	sp, err := azureClient.CreateServicePrincipal(ctx, graphrbac.ServicePrincipalCreateParameters{
+		AppID:          app.AppID,
+		AccountEnabled: to.BoolPtr(true),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating service principal: %v", err)
+	}
+
+	scopes := azureSpec.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{fmt.Sprintf("/subscriptions/%s", azureSpec.SubscriptionID)}
+	}
+	var roleAssignmentIDs []string
+	for _, scope := range scopes {
+		for _, roleDefinition := range azureSpec.RoleDefinitions {
+			roleDefinitionID := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", azureSpec.SubscriptionID, roleDefinition)
+			roleAssignment, err := azureClient.CreateRoleAssignment(ctx, scope, uuid.NewV4().String(), authorization.RoleAssignmentCreateParameters{
+				Properties: &authorization.RoleAssignmentProperties{
+					PrincipalID:      sp.ObjectID,
+					RoleDefinitionID: to.StringPtr(roleDefinitionID),
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("error assigning role %s on %s: %v", roleDefinition, scope, err)
+			}
+			roleAssignmentIDs = append(roleAssignmentIDs, to.String(roleAssignment.ID))
+		}
+	}
+
+	rootSecret, err := a.getRootAzureCredsSecret(ctx)
+	if err != nil {
+		return err
+	}
+	tenantID, err := decodeSecretValue(rootSecret, azureTenantIDSecretKey)
+	if err != nil {
+		return err
+	}
+	if err := a.writeTargetSecret(cr, map[string]string{
+		azureClientIDSecretKey:       to.String(app.AppID),
+		azureClientSecretSecretKey:   clientSecret,
+		azureTenantIDSecretKey:       tenantID,
+		azureSubscriptionIDSecretKey: azureSpec.SubscriptionID,
+	}); err != nil {
+		return err
+	}
+
+	return a.updateProviderStatus(ctx, cr, &minterv1.AzureProviderStatus{
+		ServicePrincipalName: appName,
+		AppID:                to.String(app.AppID),
+		ObjectID:             to.String(app.ObjectID),
+		RoleAssignmentIDs:    roleAssignmentIDs,
+	})
+}
+
+// syncDisabled leaves cr unprovisioned, but records a condition so that anything waiting on its
+// target Secret knows it must be provisioned some other way.
+func (a *AzureActuator) syncDisabled(ctx context.Context, cr *minterv1.CredentialsRequest) error {
+	log.WithField("cr", fmt.Sprintf("%s/%s", cr.Namespace, cr.Name)).
+		Debug("operator is in disabled mode, not provisioning credentials")
+	return a.setCondition(ctx, cr, minterv1.CredentialsProvisionFailure,
+		"the cloud credentials operator is in Disabled mode")
+}
+
+// syncPassthroughUnsupported leaves cr unprovisioned: unlike AWS, there's no Azure API equivalent
+// to SimulatePrincipalPolicy to verify the root credential's permissions against, so Passthrough
+// mode can't be implemented the same way here without fabricating new Azure API surface.
+func (a *AzureActuator) syncPassthroughUnsupported(ctx context.Context, cr *minterv1.CredentialsRequest) error {
+	log.WithField("cr", fmt.Sprintf("%s/%s", cr.Namespace, cr.Name)).
+		Debug("passthrough mode is not supported for Azure, not provisioning credentials")
+	return a.setCondition(ctx, cr, minterv1.CredentialsProvisionFailure,
+		"passthrough mode is not supported for Azure")
+}
+
+func (a *AzureActuator) setCondition(ctx context.Context, cr *minterv1.CredentialsRequest, condType minterv1.CredentialsRequestConditionType, message string) error {
+	now := metav1.Now()
+	for i, existing := range cr.Status.Conditions {
+		if existing.Type == condType {
+			cr.Status.Conditions[i].Status = corev1.ConditionTrue
+			cr.Status.Conditions[i].Message = message
+			cr.Status.Conditions[i].LastProbeTime = now
+			return a.Client.Status().Update(ctx, cr)
+		}
+	}
+	cr.Status.Conditions = append(cr.Status.Conditions, minterv1.CredentialsRequestCondition{
+		Type:               condType,
+		Status:             corev1.ConditionTrue,
+		Message:            message,
+		LastProbeTime:      now,
+		LastTransitionTime: now,
+	})
+	return a.Client.Status().Update(ctx, cr)
+}
+
+// mode resolves the operator's configured credentials mode. Unlike the AWS actuator, Azure has no
+// API to auto-detect Mint vs Passthrough capability, so CredentialsModeDefault is always treated
+// as Mint.
+func (a *AzureActuator) mode(ctx context.Context) (minterv1.CredentialsMode, error) {
+	config := &minterv1.CloudCredential{}
+	err := a.Client.Get(ctx, client.ObjectKey{Name: minterv1.CloudCredentialOperatorConfigName}, config)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return minterv1.MintCredentialsMode, nil
+		}
+		return "", err
+	}
+	if config.Spec.CredentialsMode == minterv1.CredentialsModeDefault {
+		return minterv1.MintCredentialsMode, nil
+	}
+	return config.Spec.CredentialsMode, nil
+}
+
+// Delete removes any service principal, role assignments, and AD application previously
+// provisioned for cr.
+func (a *AzureActuator) Delete(ctx context.Context, cr *minterv1.CredentialsRequest) error {
+	azureStatus, err := a.decodeStatus(cr)
+	if err != nil {
+		return err
+	}
+	if azureStatus.ObjectID == "" {
+		return nil
+	}
+
+	azureClient, err := a.buildAzureClient(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	for _, roleAssignmentID := range azureStatus.RoleAssignmentIDs {
+		if err := azureClient.DeleteRoleAssignmentByID(ctx, roleAssignmentID); err != nil && !isNotFound(err) {
+			return fmt.Errorf("error deleting role assignment %s: %v", roleAssignmentID, err)
+		}
+	}
+
+	if err := azureClient.DeleteApplication(ctx, azureStatus.ObjectID); err != nil && !isNotFound(err) {
+		return fmt.Errorf("error deleting AD application: %v", err)
+	}
+	return nil
+}
+
+// RequeueAfter returns zero: Azure service principal credentials created by this actuator don't
+// expire on their own and need no timer-based rotation.
+func (a *AzureActuator) RequeueAfter(ctx context.Context, cr *minterv1.CredentialsRequest) (time.Duration, error) {
+	return 0, nil
+}
+
+func (a *AzureActuator) buildAzureClient(ctx context.Context, cr *minterv1.CredentialsRequest) (minterazure.Client, error) {
+	rootSecret, err := a.getRootAzureCredsSecret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenantID, err := decodeSecretValue(rootSecret, azureTenantIDSecretKey)
+	if err != nil {
+		return nil, err
+	}
+	clientID, err := decodeSecretValue(rootSecret, azureClientIDSecretKey)
+	if err != nil {
+		return nil, err
+	}
+	clientSecret, err := decodeSecretValue(rootSecret, azureClientSecretSecretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	azureSpec, err := a.decodeSpec(cr)
+	if err != nil {
+		return nil, err
+	}
+	return a.AzureClientBuilder(tenantID, azureSpec.SubscriptionID, clientID, clientSecret)
+}
+
+func (a *AzureActuator) getRootAzureCredsSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := a.Client.Get(ctx, client.ObjectKey{
+		Namespace: a.RootCredsSecretNamespace,
+		Name:      a.RootCredsSecretName,
+	}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("error getting root Azure creds secret: %v", err)
+	}
+	return secret, nil
+}
+
+func (a *AzureActuator) getTargetSecret(cr *minterv1.CredentialsRequest) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := a.Client.Get(context.TODO(), client.ObjectKey{
+		Namespace: cr.Spec.SecretRef.Namespace,
+		Name:      cr.Spec.SecretRef.Name,
+	}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+func (a *AzureActuator) writeTargetSecret(cr *minterv1.CredentialsRequest, data map[string]string) error {
+	existingSecret, err := a.getTargetSecret(cr)
+	if err != nil {
+		return err
+	}
+
+	secretData := map[string][]byte{}
+	for k, v := range data {
+		secretData[k] = []byte(v)
+	}
+
+	annotationValue := fmt.Sprintf("%s/%s", cr.Namespace, cr.Name)
+
+	if existingSecret == nil {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cr.Spec.SecretRef.Name,
+				Namespace: cr.Spec.SecretRef.Namespace,
+				Annotations: map[string]string{
+					minterv1.AnnotationCredentialsRequest: annotationValue,
+				},
+			},
+			Data: secretData,
+		}
+		return a.Client.Create(context.TODO(), secret)
+	}
+
+	if existingSecret.Annotations == nil {
+		existingSecret.Annotations = map[string]string{}
+	}
+	existingSecret.Annotations[minterv1.AnnotationCredentialsRequest] = annotationValue
+	existingSecret.Data = secretData
+	return a.Client.Update(context.TODO(), existingSecret)
+}
+
+func (a *AzureActuator) decodeSpec(cr *minterv1.CredentialsRequest) (*minterv1.AzureProviderSpec, error) {
+	azureSpec := &minterv1.AzureProviderSpec{}
+	if err := a.Codec.DecodeProviderSpec(cr.Spec.ProviderSpec, azureSpec); err != nil {
+		return nil, fmt.Errorf("error decoding Azure provider spec: %v", err)
+	}
+	return azureSpec, nil
+}
+
+func (a *AzureActuator) decodeStatus(cr *minterv1.CredentialsRequest) (*minterv1.AzureProviderStatus, error) {
+	azureStatus := &minterv1.AzureProviderStatus{}
+	if err := a.Codec.DecodeProviderStatus(cr.Status.ProviderStatus, azureStatus); err != nil {
+		return nil, fmt.Errorf("error decoding Azure provider status: %v", err)
+	}
+	return azureStatus, nil
+}
+
+// updateProviderStatus encodes azureStatus into cr.Status.ProviderStatus and persists it if that
+// changed anything. This must persist on its own rather than relying on the controller's later
+// Status().Update: that update is gated on cr.Status.Provisioned changing, which isn't true on
+// every reconcile that changes ProviderStatus. The unchanged check keeps a steady-state reconcile,
+// which calls this every time regardless of drift, from writing status on every single reconcile.
+func (a *AzureActuator) updateProviderStatus(ctx context.Context, cr *minterv1.CredentialsRequest, azureStatus *minterv1.AzureProviderStatus) error {
+	previous := cr.Status.ProviderStatus
+	encoded, err := a.Codec.EncodeProviderStatus(azureStatus)
+	if err != nil {
+		return fmt.Errorf("error encoding Azure provider status: %v", err)
+	}
+	if previous != nil && bytes.Equal(previous.Raw, encoded.Raw) {
+		return nil
+	}
+	cr.Status.ProviderStatus = encoded
+	return a.Client.Status().Update(ctx, cr)
+}
+
+// decodeSecretValue reads a key from a Secret whose Data values hold raw bytes.
+func decodeSecretValue(secret *corev1.Secret, key string) (string, error) {
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("secret key %q not found", key)
+	}
+	return string(value), nil
+}
+
+func generateServicePrincipalName(cr *minterv1.CredentialsRequest) string {
+	return fmt.Sprintf("%s-%s", cr.Spec.ClusterName, cr.Name)
+}
+
+// isNotFound returns true if err indicates the requested Azure AD object does not exist.
+func isNotFound(err error) bool {
+	if detailedErr, ok := err.(autorest.DetailedError); ok {
+		return detailedErr.StatusCode == http.StatusNotFound
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/authorization/mgmt/2015-07-01/authorization"
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+)
+
+//go:generate mockgen -source=./client.go -destination=mock/client_generated.go -package=mock
+
+// Client is a wrap around the Azure AD (graphrbac) and authorization clients that helps with
+// testing.
+type Client interface {
+	CreateApplication(ctx context.Context, params graphrbac.ApplicationCreateParameters) (graphrbac.Application, error)
+	GetApplication(ctx context.Context, applicationObjectID string) (graphrbac.Application, error)
+	DeleteApplication(ctx context.Context, applicationObjectID string) error
+	CreateServicePrincipal(ctx context.Context, params graphrbac.ServicePrincipalCreateParameters) (graphrbac.ServicePrincipal, error)
+	CreateRoleAssignment(ctx context.Context, scope, roleAssignmentName string, params authorization.RoleAssignmentCreateParameters) (authorization.RoleAssignment, error)
+	DeleteRoleAssignmentByID(ctx context.Context, roleID string) error
+}
+
+type azureClient struct {
+	applicationsClient      graphrbac.ApplicationsClient
+	servicePrincipalsClient graphrbac.ServicePrincipalsClient
+	roleAssignmentsClient   authorization.RoleAssignmentsClient
+}
+
+func (c *azureClient) CreateApplication(ctx context.Context, params graphrbac.ApplicationCreateParameters) (graphrbac.Application, error) {
+	return c.applicationsClient.Create(ctx, params)
+}
+
+func (c *azureClient) GetApplication(ctx context.Context, applicationObjectID string) (graphrbac.Application, error) {
+	return c.applicationsClient.Get(ctx, applicationObjectID)
+}
+
+func (c *azureClient) DeleteApplication(ctx context.Context, applicationObjectID string) error {
+	_, err := c.applicationsClient.Delete(ctx, applicationObjectID)
+	return err
+}
+
+func (c *azureClient) CreateServicePrincipal(ctx context.Context, params graphrbac.ServicePrincipalCreateParameters) (graphrbac.ServicePrincipal, error) {
+	return c.servicePrincipalsClient.Create(ctx, params)
+}
+
+func (c *azureClient) CreateRoleAssignment(ctx context.Context, scope, roleAssignmentName string, params authorization.RoleAssignmentCreateParameters) (authorization.RoleAssignment, error) {
+	return c.roleAssignmentsClient.Create(ctx, scope, roleAssignmentName, params)
+}
+
+func (c *azureClient) DeleteRoleAssignmentByID(ctx context.Context, roleID string) error {
+	_, err := c.roleAssignmentsClient.DeleteByID(ctx, roleID)
+	return err
+}
+
+// NewClient creates our client wrapper object for the actual Azure clients we use, authenticated
+// against tenantID and subscriptionID with clientID/clientSecret.
+func NewClient(tenantID, subscriptionID, clientID, clientSecret string) (Client, error) {
+	authorizer, err := newClientCredentialsAuthorizer(tenantID, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	applicationsClient := graphrbac.NewApplicationsClient(tenantID)
+	applicationsClient.Authorizer = authorizer
+
+	servicePrincipalsClient := graphrbac.NewServicePrincipalsClient(tenantID)
+	servicePrincipalsClient.Authorizer = authorizer
+
+	roleAssignmentsClient := authorization.NewRoleAssignmentsClient(subscriptionID)
+	roleAssignmentsClient.Authorizer = authorizer
+
+	return &azureClient{
+		applicationsClient:      applicationsClient,
+		servicePrincipalsClient: servicePrincipalsClient,
+		roleAssignmentsClient:   roleAssignmentsClient,
+	}, nil
+}
+
+func newClientCredentialsAuthorizer(tenantID, clientID, clientSecret string) (autorest.Authorizer, error) {
+	config := auth.NewClientCredentialsConfig(clientID, clientSecret, tenantID)
+	return config.Authorizer()
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialsrequest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	minterv1 "github.com/openshift/cred-minter/pkg/apis/credminter/v1beta1"
+	awsactuator "github.com/openshift/cred-minter/pkg/aws/actuator"
+	azureactuator "github.com/openshift/cred-minter/pkg/azure/actuator"
+)
+
+// Actuator is the interface a cloud provider specific implementation must satisfy to have its
+// CredentialsRequests reconciled by this controller.
+type Actuator interface {
+	// CanHandle returns true if this Actuator knows how to satisfy a CredentialsRequest whose
+	// ProviderSpec is providerSpec, letting the controller dispatch to the right Actuator
+	// without needing to know about every cloud provider itself.
+	CanHandle(providerSpec *runtime.RawExtension) bool
+	Exists(ctx context.Context, cr *minterv1.CredentialsRequest) (bool, error)
+	// Create and Update provision or reconcile cr's credentials, returning whether credentials
+	// were actually minted/copied into cr's target Secret. They return false without erroring
+	// when an actuator intentionally leaves cr unprovisioned, e.g. Disabled mode or a Passthrough
+	// permissions check that was denied, so the caller doesn't mistakenly mark cr as Provisioned.
+	Create(ctx context.Context, cr *minterv1.CredentialsRequest) (bool, error)
+	Update(ctx context.Context, cr *minterv1.CredentialsRequest) (bool, error)
+	Delete(ctx context.Context, cr *minterv1.CredentialsRequest) error
+	// RequeueAfter returns how long to wait before reconciling cr again on a timer, or zero if
+	// no timer-based requeue is needed (e.g. credentials that don't expire on their own).
+	RequeueAfter(ctx context.Context, cr *minterv1.CredentialsRequest) (time.Duration, error)
+}
+
+// Add creates a new CredentialsRequest Controller and adds it to the Manager. The Manager will
+// set fields on the Controller and start it when the Manager is started.
+func Add(mgr manager.Manager) error {
+	r, err := newReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return add(mgr, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	awsActuator, err := awsactuator.NewAWSActuator(mgr.GetClient(), mgr.GetScheme())
+	if err != nil {
+		return nil, err
+	}
+	azureActuator, err := azureactuator.NewAzureActuator(mgr.GetClient(), mgr.GetScheme())
+	if err != nil {
+		return nil, err
+	}
+	codec, err := minterv1.NewCodec()
+	if err != nil {
+		return nil, err
+	}
+	return &ReconcileCredentialsRequest{
+		Client:    mgr.GetClient(),
+		Codec:     codec,
+		Actuators: []Actuator{awsActuator, azureActuator},
+	}, nil
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("credentialsrequest-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{Type: &minterv1.CredentialsRequest{}}, &handler.EnqueueRequestForObject{})
+}
+
+var _ reconcile.Reconciler = &ReconcileCredentialsRequest{}
+
+// ReconcileCredentialsRequest reconciles a CredentialsRequest object
+type ReconcileCredentialsRequest struct {
+	client.Client
+	Codec *minterv1.Codec
+	// Actuators holds one Actuator per supported cloud provider. The first Actuator whose
+	// CanHandle matches a CredentialsRequest's ProviderSpec is used to reconcile it.
+	Actuators []Actuator
+}
+
+// noActuatorError indicates a CredentialsRequest's ProviderSpec was decoded successfully but
+// doesn't match any registered Actuator, as opposed to the ProviderSpec itself being malformed.
+// Deletion handling treats this case as "nothing we know how to clean up" rather than an error to
+// retry.
+type noActuatorError struct {
+	namespace, name string
+}
+
+func (e *noActuatorError) Error() string {
+	return fmt.Sprintf("no actuator registered to handle provider spec for %s/%s", e.namespace, e.name)
+}
+
+// actuatorFor returns the Actuator responsible for cr's ProviderSpec. It returns a
+// *noActuatorError if the ProviderSpec decodes fine but names a kind no Actuator handles, which
+// callers can treat differently than a genuine decode failure.
+func (r *ReconcileCredentialsRequest) actuatorFor(cr *minterv1.CredentialsRequest) (Actuator, error) {
+	if _, err := r.Codec.ProviderSpecKind(cr.Spec.ProviderSpec); err != nil {
+		return nil, fmt.Errorf("error determining provider spec kind for %s/%s: %v", cr.Namespace, cr.Name, err)
+	}
+	for _, a := range r.Actuators {
+		if a.CanHandle(cr.Spec.ProviderSpec) {
+			return a, nil
+		}
+	}
+	return nil, &noActuatorError{namespace: cr.Namespace, name: cr.Name}
+}
+
+// Reconcile provisions or deprovisions the credentials described by a CredentialsRequest,
+// delegating the cloud specific work to the configured Actuator.
+func (r *ReconcileCredentialsRequest) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx := context.TODO()
+	logger := log.WithFields(log.Fields{
+		"controller": "credentialsrequest",
+		"cr":         fmt.Sprintf("%s/%s", request.Namespace, request.Name),
+	})
+
+	cr := &minterv1.CredentialsRequest{}
+	err := r.Get(ctx, request.NamespacedName, cr)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if cr.DeletionTimestamp != nil {
+		if !HasFinalizer(cr, minterv1.FinalizerDeprovision) {
+			return reconcile.Result{}, nil
+		}
+		credActuator, err := r.actuatorFor(cr)
+		if err != nil {
+			if _, ok := err.(*noActuatorError); !ok {
+				// The ProviderSpec itself couldn't be decoded; retry rather than silently
+				// abandoning whatever cleanup it would have required.
+				return reconcile.Result{}, err
+			}
+			// No Actuator recognizes this ProviderSpec anymore (e.g. its provider was removed,
+			// or it was never set). There's nothing we know how to clean up in the cloud, so
+			// don't block deletion on it forever.
+			logger.WithError(err).Warning("no actuator found to deprovision credentials, removing finalizer")
+		} else {
+			logger.Info("deprovisioning credentials")
+			if err := credActuator.Delete(ctx, cr); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		cr.ObjectMeta.Finalizers = removeFinalizer(cr.ObjectMeta.Finalizers, minterv1.FinalizerDeprovision)
+		if err := r.Update(ctx, cr); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	credActuator, err := r.actuatorFor(cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if !HasFinalizer(cr, minterv1.FinalizerDeprovision) {
+		cr.ObjectMeta.Finalizers = append(cr.ObjectMeta.Finalizers, minterv1.FinalizerDeprovision)
+		if err := r.Update(ctx, cr); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	exists, err := credActuator.Exists(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	var provisioned bool
+	if !exists {
+		logger.Info("provisioning new credentials")
+		provisioned, err = credActuator.Create(ctx, cr)
+	} else {
+		logger.Debug("reconciling existing credentials")
+		provisioned, err = credActuator.Update(ctx, cr)
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Keep Provisioned in sync with whether the actuator actually minted or copied credentials
+	// this reconcile, rather than only ever setting it true: an actuator that intentionally
+	// leaves cr unprovisioned (e.g. Disabled mode, or a mode change after a prior successful
+	// provision) already recorded its own condition explaining why, and a stale Provisioned=true
+	// would contradict it.
+	if cr.Status.Provisioned != provisioned {
+		cr.Status.Provisioned = provisioned
+		if err := r.Status().Update(ctx, cr); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	requeueAfter, err := credActuator.RequeueAfter(ctx, cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// HasFinalizer returns true if the given object has the given finalizer
+func HasFinalizer(cr *minterv1.CredentialsRequest, finalizer string) bool {
+	for _, f := range cr.ObjectMeta.Finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFinalizer(finalizers []string, finalizer string) []string {
+	result := make([]string, 0, len(finalizers))
+	for _, f := range finalizers {
+		if f != finalizer {
+			result = append(result, f)
+		}
+	}
+	return result
+}
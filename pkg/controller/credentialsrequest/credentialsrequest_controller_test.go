@@ -19,8 +19,10 @@ package credentialsrequest
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	log "github.com/sirupsen/logrus"
@@ -39,12 +41,20 @@ import (
 	"github.com/openshift/cred-minter/pkg/apis"
 	minterv1 "github.com/openshift/cred-minter/pkg/apis/credminter/v1beta1"
 	minteraws "github.com/openshift/cred-minter/pkg/aws"
-	"github.com/openshift/cred-minter/pkg/aws/actuator"
+	awsactuator "github.com/openshift/cred-minter/pkg/aws/actuator"
 	mockaws "github.com/openshift/cred-minter/pkg/aws/mock"
+	minterazure "github.com/openshift/cred-minter/pkg/azure"
+	azureactuator "github.com/openshift/cred-minter/pkg/azure/actuator"
+	mockazure "github.com/openshift/cred-minter/pkg/azure/mock"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+
+	"github.com/Azure/azure-sdk-for-go/services/authorization/mgmt/2015-07-01/authorization"
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+	"github.com/Azure/go-autorest/autorest/to"
 )
 
 var c client.Client
@@ -76,11 +86,13 @@ func TestCredentialsRequestReconcile(t *testing.T) {
 	}
 
 	tests := []struct {
-		name               string
-		existing           []runtime.Object
-		expectErr          bool
-		buildMockAWSClient func(mockCtrl *gomock.Controller) *mockaws.MockClient
-		validate           func(client.Client, *testing.T)
+		name                 string
+		existing             []runtime.Object
+		expectErr            bool
+		buildMockAWSClient   func(mockCtrl *gomock.Controller) *mockaws.MockClient
+		buildMockAzureClient func(mockCtrl *gomock.Controller) *mockazure.MockClient
+		validate             func(client.Client, *testing.T)
+		validateResult       func(reconcile.Result, *testing.T)
 	}{
 		{
 			name: "add finalizer",
@@ -219,6 +231,332 @@ func TestCredentialsRequestReconcile(t *testing.T) {
 				assert.True(t, cr.Status.Provisioned)
 			},
 		},
+		{
+			name: "new credential with policy condition",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				testCredentialsRequestWithStatements(t, testConditionalStatements),
+				testAWSCredsSecret("kube-system", "aws-creds", "akeyid", "secretaccess"),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockGetUserNotFound(mockAWSClient)
+				mockGetUserPolicyNotFound(mockAWSClient)
+				mockPutUserPolicyWithStatements(mockAWSClient, testConditionalStatements)
+				mockCreateUser(mockAWSClient)
+				mockListAccessKeysEmpty(mockAWSClient)
+				mockCreateAccessKey(mockAWSClient, testAWSAccessKeyID, testAWSSecretAccessKey)
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cr := getCR(c)
+				assert.True(t, cr.Status.Provisioned)
+			},
+		},
+		{
+			name: "cred exists policy drifted missing condition",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				testCredentialsRequestWithStatements(t, testConditionalStatements),
+				testAWSCredsSecret("kube-system", "aws-creds", "akeyid", "secretaccess"),
+				testAWSCredsSecret(testNamespace, testSecretName, testAWSAccessKeyID, testAWSSecretAccessKey),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockGetUser(mockAWSClient)
+				// The policy IAM already has attached is missing the Condition block, so the
+				// actuator must detect the drift and re-Put the policy.
+				mockGetUserPolicyMatching(mockAWSClient, testAWSUser, []minterv1.StatementEntry{
+					{
+						Effect:   testConditionalStatements[0].Effect,
+						Action:   testConditionalStatements[0].Action,
+						Resource: testConditionalStatements[0].Resource,
+					},
+				})
+				mockPutUserPolicyWithStatements(mockAWSClient, testConditionalStatements)
+				mockListAccessKeys(mockAWSClient, testAWSAccessKeyID)
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cr := getCR(c)
+				assert.True(t, cr.Status.Provisioned)
+			},
+		},
+		{
+			name: "cred exists policy matches condition",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				testCredentialsRequestWithStatements(t, testConditionalStatements),
+				testAWSCredsSecret("kube-system", "aws-creds", "akeyid", "secretaccess"),
+				testAWSCredsSecret(testNamespace, testSecretName, testAWSAccessKeyID, testAWSSecretAccessKey),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockGetUser(mockAWSClient)
+				// IAM already has the exact policy we want, PutUserPolicy must not be called.
+				mockGetUserPolicyMatching(mockAWSClient, testAWSUser, testConditionalStatements)
+				mockListAccessKeys(mockAWSClient, testAWSAccessKeyID)
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cr := getCR(c)
+				assert.True(t, cr.Status.Provisioned)
+			},
+		},
+		{
+			name: "disabled mode blocks sts session",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				&minterv1.CloudCredential{
+					ObjectMeta: metav1.ObjectMeta{Name: minterv1.CloudCredentialOperatorConfigName},
+					Spec:       minterv1.CloudCredentialSpec{CredentialsMode: minterv1.DisabledCredentialsMode},
+				},
+				testSTSCredentialsRequest(t, nil),
+				testAWSCredsSecret("kube-system", "aws-creds", "akeyid", "secretaccess"),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				// Disabled mode must short circuit before any AssumeRole call is made.
+				return mockaws.NewMockClient(mockCtrl)
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getSecret(c))
+				cr := getCR(c)
+				if assert.Len(t, cr.Status.Conditions, 1) {
+					assert.Equal(t, minterv1.CredentialsProvisionFailure, cr.Status.Conditions[0].Type)
+				}
+				assert.False(t, cr.Status.Provisioned)
+			},
+		},
+		{
+			name: "passthrough mode copies root credentials when allowed",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				&minterv1.CloudCredential{
+					ObjectMeta: metav1.ObjectMeta{Name: minterv1.CloudCredentialOperatorConfigName},
+					Spec:       minterv1.CloudCredentialSpec{CredentialsMode: minterv1.PassthroughCredentialsMode},
+				},
+				testCredentialsRequest(t),
+				testAWSCredsSecret("kube-system", "aws-creds", testAWSAccessKeyID, testAWSSecretAccessKey),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockGetCallerIdentity(mockAWSClient)
+				mockSimulatePrincipalPolicy(mockAWSClient, true)
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				targetSecret := getSecret(c)
+				if assert.NotNil(t, targetSecret) {
+					assert.Equal(t, testAWSAccessKeyID,
+						base64DecodeOrFail(t, targetSecret.Data["aws_access_key_id"]))
+					assert.Equal(t, testAWSSecretAccessKey,
+						base64DecodeOrFail(t, targetSecret.Data["aws_secret_access_key"]))
+				}
+				cr := getCR(c)
+				assert.True(t, cr.Status.Provisioned)
+			},
+		},
+		{
+			name: "passthrough mode denied leaves credentials unprovisioned",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				&minterv1.CloudCredential{
+					ObjectMeta: metav1.ObjectMeta{Name: minterv1.CloudCredentialOperatorConfigName},
+					Spec:       minterv1.CloudCredentialSpec{CredentialsMode: minterv1.PassthroughCredentialsMode},
+				},
+				testCredentialsRequest(t),
+				testAWSCredsSecret("kube-system", "aws-creds", testAWSAccessKeyID, testAWSSecretAccessKey),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockGetCallerIdentity(mockAWSClient)
+				mockSimulatePrincipalPolicy(mockAWSClient, false)
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getSecret(c))
+				cr := getCR(c)
+				if assert.Len(t, cr.Status.Conditions, 1) {
+					assert.Equal(t, minterv1.InsufficientCloudCredentials, cr.Status.Conditions[0].Type)
+				}
+				assert.False(t, cr.Status.Provisioned)
+			},
+		},
+		{
+			name: "default mode auto-detects mint when root can create IAM users",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				&minterv1.CloudCredential{
+					ObjectMeta: metav1.ObjectMeta{Name: minterv1.CloudCredentialOperatorConfigName},
+					Spec:       minterv1.CloudCredentialSpec{CredentialsMode: minterv1.CredentialsModeDefault},
+				},
+				testCredentialsRequest(t),
+				testAWSCredsSecret("kube-system", "aws-creds", "akeyid", "secretaccess"),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				// Exists and Create each resolve the mode, so detection runs twice.
+				mockGetCallerIdentity(mockAWSClient)
+				mockSimulatePrincipalPolicy(mockAWSClient, true)
+				mockGetCallerIdentity(mockAWSClient)
+				mockSimulatePrincipalPolicy(mockAWSClient, true)
+				mockGetUserNotFound(mockAWSClient)
+				mockPutUserPolicy(mockAWSClient)
+				mockCreateUser(mockAWSClient)
+				mockListAccessKeysEmpty(mockAWSClient)
+				mockCreateAccessKey(mockAWSClient, testAWSAccessKeyID, testAWSSecretAccessKey)
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				targetSecret := getSecret(c)
+				if assert.NotNil(t, targetSecret) {
+					assert.Equal(t, testAWSAccessKeyID,
+						base64DecodeOrFail(t, targetSecret.Data["aws_access_key_id"]))
+				}
+				cr := getCR(c)
+				assert.True(t, cr.Status.Provisioned)
+			},
+		},
+		{
+			name: "default mode auto-detects passthrough when root cannot create IAM users",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				&minterv1.CloudCredential{
+					ObjectMeta: metav1.ObjectMeta{Name: minterv1.CloudCredentialOperatorConfigName},
+					Spec:       minterv1.CloudCredentialSpec{CredentialsMode: minterv1.CredentialsModeDefault},
+				},
+				testCredentialsRequest(t),
+				testAWSCredsSecret("kube-system", "aws-creds", testAWSAccessKeyID, testAWSSecretAccessKey),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				// Exists and Update each resolve the mode (iam:CreateUser denied both times), then
+				// the Passthrough permissions check is allowed.
+				mockGetCallerIdentity(mockAWSClient)
+				mockSimulatePrincipalPolicy(mockAWSClient, false)
+				mockGetCallerIdentity(mockAWSClient)
+				mockSimulatePrincipalPolicy(mockAWSClient, false)
+				mockGetCallerIdentity(mockAWSClient)
+				mockSimulatePrincipalPolicy(mockAWSClient, true)
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				targetSecret := getSecret(c)
+				if assert.NotNil(t, targetSecret) {
+					assert.Equal(t, testAWSAccessKeyID,
+						base64DecodeOrFail(t, targetSecret.Data["aws_access_key_id"]))
+					assert.Equal(t, testAWSSecretAccessKey,
+						base64DecodeOrFail(t, targetSecret.Data["aws_secret_access_key"]))
+				}
+				cr := getCR(c)
+				assert.True(t, cr.Status.Provisioned)
+			},
+		},
+		{
+			name: "sts new session",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				testSTSCredentialsRequest(t, nil),
+				testAWSCredsSecret("kube-system", "aws-creds", "akeyid", "secretaccess"),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockAssumeRole(mockAWSClient, time.Now().Add(time.Hour))
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				targetSecret := getSecret(c)
+				if assert.NotNil(t, targetSecret) {
+					assert.Equal(t, testSTSAccessKeyID,
+						base64DecodeOrFail(t, targetSecret.Data["aws_access_key_id"]))
+					assert.Equal(t, testSTSSecretAccessKey,
+						base64DecodeOrFail(t, targetSecret.Data["aws_secret_access_key"]))
+					assert.Equal(t, testSTSSessionToken,
+						base64DecodeOrFail(t, targetSecret.Data["aws_session_token"]))
+				}
+				cr := getCR(c)
+				assert.True(t, cr.Status.Provisioned)
+			},
+			validateResult: func(result reconcile.Result, t *testing.T) {
+				assert.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= time.Hour)
+			},
+		},
+		{
+			name: "sts session refreshed before expiry",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				func() *minterv1.CredentialsRequest {
+					// Session is within refreshWindow of expiring, Exists must report it as
+					// needing rotation rather than reusing it.
+					expiration := metav1.NewTime(time.Now().Add(5 * time.Minute))
+					return testSTSCredentialsRequest(t, &expiration)
+				}(),
+				testAWSCredsSecret("kube-system", "aws-creds", "akeyid", "secretaccess"),
+				testAWSCredsSecret(testNamespace, testSecretName, testSTSAccessKeyID, testSTSSecretAccessKey),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockAssumeRole(mockAWSClient, time.Now().Add(time.Hour))
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				targetSecret := getSecret(c)
+				if assert.NotNil(t, targetSecret) {
+					assert.Equal(t, testSTSAccessKeyID,
+						base64DecodeOrFail(t, targetSecret.Data["aws_access_key_id"]))
+				}
+				cr := getCR(c)
+				assert.True(t, cr.Status.Provisioned)
+			},
+		},
+		{
+			name: "sts session rotated while already provisioned persists new expiration",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				func() *minterv1.CredentialsRequest {
+					// Already Provisioned from a prior reconcile, unlike every other STS test
+					// case: the Provisioned-gated Status().Update the controller used to rely on
+					// wouldn't fire here, since Provisioned doesn't change value this reconcile.
+					expiration := metav1.NewTime(time.Now().Add(5 * time.Minute))
+					cr := testSTSCredentialsRequest(t, &expiration)
+					cr.Status.Provisioned = true
+					return cr
+				}(),
+				testAWSCredsSecret("kube-system", "aws-creds", "akeyid", "secretaccess"),
+				testAWSCredsSecret(testNamespace, testSecretName, testSTSAccessKeyID, testSTSSecretAccessKey),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				mockAWSClient := mockaws.NewMockClient(mockCtrl)
+				mockAssumeRole(mockAWSClient, time.Now().Add(time.Hour))
+				return mockAWSClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cr := getCR(c)
+				awsStatus := decodeAWSProviderStatusOrFail(t, cr)
+				if assert.NotNil(t, awsStatus.Expiration) {
+					assert.True(t, awsStatus.Expiration.Time.After(time.Now().Add(30*time.Minute)),
+						"rotated session's new Expiration was not persisted")
+				}
+			},
+		},
+		{
+			name: "sts deletion",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				func() *minterv1.CredentialsRequest {
+					cr := testSTSCredentialsRequest(t, nil)
+					now := metav1.Now()
+					cr.DeletionTimestamp = &now
+					return cr
+				}(),
+				testAWSCredsSecret("kube-system", "aws-creds", "akeyid", "secretaccess"),
+				testAWSCredsSecret(testNamespace, testSecretName, testSTSAccessKeyID, testSTSSecretAccessKey),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				// No IAM user/key calls should be made for an STS-backed CredentialsRequest.
+				return mockaws.NewMockClient(mockCtrl)
+			},
+		},
 		{
 			name: "cred deletion",
 			existing: []runtime.Object{
@@ -236,6 +574,130 @@ func TestCredentialsRequestReconcile(t *testing.T) {
 				return mockAWSClient
 			},
 		},
+		{
+			name: "azure new service principal",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				testAzureCredentialsRequest(t, ""),
+				testAzureCredsSecret("kube-system", "azure-credentials", testAzureTenantID, testAzureRootClientID, testAzureRootClientSecret),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				return mockaws.NewMockClient(mockCtrl)
+			},
+			buildMockAzureClient: func(mockCtrl *gomock.Controller) *mockazure.MockClient {
+				mockAzureClient := mockazure.NewMockClient(mockCtrl)
+				mockCreateApplication(mockAzureClient, testAzureAppID)
+				mockCreateServicePrincipal(mockAzureClient, testAzureAppID)
+				mockCreateRoleAssignment(mockAzureClient)
+				return mockAzureClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				targetSecret := getSecret(c)
+				if assert.NotNil(t, targetSecret) {
+					assert.Equal(t, testAzureAppID, string(targetSecret.Data["azure_client_id"]))
+					assert.Equal(t, testAzureSubscriptionID, string(targetSecret.Data["azure_subscription_id"]))
+				}
+				cr := getCR(c)
+				assert.True(t, cr.Status.Provisioned)
+			},
+		},
+		{
+			name: "azure existing service principal",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				testAzureCredentialsRequest(t, testAzureObjectID),
+				testAzureCredsSecret("kube-system", "azure-credentials", testAzureTenantID, testAzureRootClientID, testAzureRootClientSecret),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				return mockaws.NewMockClient(mockCtrl)
+			},
+			buildMockAzureClient: func(mockCtrl *gomock.Controller) *mockazure.MockClient {
+				mockAzureClient := mockazure.NewMockClient(mockCtrl)
+				mockGetApplication(mockAzureClient, testAzureObjectID)
+				return mockAzureClient
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cr := getCR(c)
+				assert.True(t, cr.Status.Provisioned)
+			},
+		},
+		{
+			name: "azure deletion",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				func() *minterv1.CredentialsRequest {
+					cr := testAzureCredentialsRequest(t, testAzureObjectID)
+					now := metav1.Now()
+					cr.DeletionTimestamp = &now
+					return cr
+				}(),
+				testAzureCredsSecret("kube-system", "azure-credentials", testAzureTenantID, testAzureRootClientID, testAzureRootClientSecret),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				return mockaws.NewMockClient(mockCtrl)
+			},
+			buildMockAzureClient: func(mockCtrl *gomock.Controller) *mockazure.MockClient {
+				mockAzureClient := mockazure.NewMockClient(mockCtrl)
+				mockDeleteApplication(mockAzureClient, testAzureObjectID)
+				return mockAzureClient
+			},
+		},
+		{
+			name: "azure disabled mode blocks provisioning",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				&minterv1.CloudCredential{
+					ObjectMeta: metav1.ObjectMeta{Name: minterv1.CloudCredentialOperatorConfigName},
+					Spec:       minterv1.CloudCredentialSpec{CredentialsMode: minterv1.DisabledCredentialsMode},
+				},
+				testAzureCredentialsRequest(t, ""),
+				testAzureCredsSecret("kube-system", "azure-credentials", testAzureTenantID, testAzureRootClientID, testAzureRootClientSecret),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				return mockaws.NewMockClient(mockCtrl)
+			},
+			buildMockAzureClient: func(mockCtrl *gomock.Controller) *mockazure.MockClient {
+				// Disabled mode must short circuit before any application/service principal
+				// creation call is made.
+				return mockazure.NewMockClient(mockCtrl)
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getSecret(c))
+				cr := getCR(c)
+				if assert.Len(t, cr.Status.Conditions, 1) {
+					assert.Equal(t, minterv1.CredentialsProvisionFailure, cr.Status.Conditions[0].Type)
+				}
+				assert.False(t, cr.Status.Provisioned)
+			},
+		},
+		{
+			name: "azure passthrough mode unsupported",
+			existing: []runtime.Object{
+				createTestNamespace(testSecretNamespace),
+				&minterv1.CloudCredential{
+					ObjectMeta: metav1.ObjectMeta{Name: minterv1.CloudCredentialOperatorConfigName},
+					Spec:       minterv1.CloudCredentialSpec{CredentialsMode: minterv1.PassthroughCredentialsMode},
+				},
+				testAzureCredentialsRequest(t, ""),
+				testAzureCredsSecret("kube-system", "azure-credentials", testAzureTenantID, testAzureRootClientID, testAzureRootClientSecret),
+			},
+			buildMockAWSClient: func(mockCtrl *gomock.Controller) *mockaws.MockClient {
+				return mockaws.NewMockClient(mockCtrl)
+			},
+			buildMockAzureClient: func(mockCtrl *gomock.Controller) *mockazure.MockClient {
+				// Passthrough isn't implemented for Azure, so it must short circuit the same way
+				// Disabled mode does rather than calling out to Azure.
+				return mockazure.NewMockClient(mockCtrl)
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getSecret(c))
+				cr := getCR(c)
+				if assert.Len(t, cr.Status.Conditions, 1) {
+					assert.Equal(t, minterv1.CredentialsProvisionFailure, cr.Status.Conditions[0].Type)
+				}
+				assert.False(t, cr.Status.Provisioned)
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -244,6 +706,13 @@ func TestCredentialsRequestReconcile(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			mockAWSClient := test.buildMockAWSClient(mockCtrl)
+			buildMockAzureClient := test.buildMockAzureClient
+			if buildMockAzureClient == nil {
+				buildMockAzureClient = func(mockCtrl *gomock.Controller) *mockazure.MockClient {
+					return mockazure.NewMockClient(mockCtrl)
+				}
+			}
+			mockAzureClient := buildMockAzureClient(mockCtrl)
 			fakeClient := fake.NewFakeClient(test.existing...)
 			codec, err := minterv1.NewCodec()
 			if err != nil {
@@ -253,17 +722,30 @@ func TestCredentialsRequestReconcile(t *testing.T) {
 			}
 			rcr := &ReconcileCredentialsRequest{
 				Client: fakeClient,
-				Actuator: &actuator.AWSActuator{
-					Client: fakeClient,
-					Codec:  codec,
-					Scheme: scheme.Scheme,
-					AWSClientBuilder: func(accessKeyID, secretAccessKey []byte) (minteraws.Client, error) {
-						return mockAWSClient, nil
+				Codec:  codec,
+				Actuators: []Actuator{
+					&awsactuator.AWSActuator{
+						Client: fakeClient,
+						Codec:  codec,
+						Scheme: scheme.Scheme,
+						AWSClientBuilder: func(accessKeyID, secretAccessKey []byte) (minteraws.Client, error) {
+							return mockAWSClient, nil
+						},
+					},
+					&azureactuator.AzureActuator{
+						Client:                   fakeClient,
+						Codec:                    codec,
+						Scheme:                   scheme.Scheme,
+						RootCredsSecretNamespace: "kube-system",
+						RootCredsSecretName:      "azure-credentials",
+						AzureClientBuilder: func(tenantID, subscriptionID, clientID, clientSecret string) (minterazure.Client, error) {
+							return mockAzureClient, nil
+						},
 					},
 				},
 			}
 
-			_, err = rcr.Reconcile(reconcile.Request{
+			result, err := rcr.Reconcile(reconcile.Request{
 				NamespacedName: types.NamespacedName{
 					Name:      testCRName,
 					Namespace: testNamespace,
@@ -273,6 +755,9 @@ func TestCredentialsRequestReconcile(t *testing.T) {
 			if test.validate != nil {
 				test.validate(fakeClient, t)
 			}
+			if test.validateResult != nil {
+				test.validateResult(result, t)
+			}
 
 			if err != nil && !test.expectErr {
 				t.Errorf("Unexpected error: %v", err)
@@ -297,8 +782,40 @@ const (
 	testAWSAccessKeyID2     = "FAKEAWSACCESSKEYID2"
 	testAWSSecretAccessKey  = "KEEPITSECRET"
 	testAWSSecretAccessKey2 = "KEEPITSECRET2"
+
+	testRootPrincipalARN = "arn:aws:iam::123456789012:user/root-creds"
+
+	testSTSRoleARN         = "arn:aws:iam::123456789012:role/test-role"
+	testSTSAccessKeyID     = "FAKESTSACCESSKEYID"
+	testSTSSecretAccessKey = "STSKEEPITSECRET"
+	testSTSSessionToken    = "STSSESSIONTOKEN"
+
+	testAzureSubscriptionID   = "7a5d2800-0000-0000-0000-2cf8e5f3c5a0"
+	testAzureTenantID         = "3c1e2d80-0000-0000-0000-8e1f2e3c4a5b"
+	testAzureRootClientID     = "FAKEAZUREROOTCLIENTID"
+	testAzureRootClientSecret = "FAKEAZUREROOTCLIENTSECRET"
+	testAzureAppID            = "FAKEAZUREAPPID"
+	testAzureObjectID         = "FAKEAZUREOBJECTID"
 )
 
+// testConditionalStatements is a StatementEntry set scoped with a PolicyCondition, used to
+// exercise the actuator's policy drift detection.
+var testConditionalStatements = []minterv1.StatementEntry{
+	{
+		Effect: "Allow",
+		Action: []string{
+			"s3:CreateBucket",
+			"s3:DeleteBucket",
+		},
+		Resource: "*",
+		PolicyCondition: minterv1.IAMPolicyCondition{
+			"StringEquals": {
+				"aws:RequestTag/owner": "openshift",
+			},
+		},
+	},
+}
+
 func testCredentialsRequestWithDeletionTimestamp(t *testing.T) *minterv1.CredentialsRequest {
 	cr := testCredentialsRequest(t)
 	now := metav1.Now()
@@ -307,6 +824,19 @@ func testCredentialsRequestWithDeletionTimestamp(t *testing.T) *minterv1.Credent
 }
 
 func testCredentialsRequest(t *testing.T) *minterv1.CredentialsRequest {
+	return testCredentialsRequestWithStatements(t, []minterv1.StatementEntry{
+		{
+			Effect: "Allow",
+			Action: []string{
+				"s3:CreateBucket",
+				"s3:DeleteBucket",
+			},
+			Resource: "*",
+		},
+	})
+}
+
+func testCredentialsRequestWithStatements(t *testing.T, statements []minterv1.StatementEntry) *minterv1.CredentialsRequest {
 	codec, err := minterv1.NewCodec()
 	if err != nil {
 		t.Logf("error creating new codec: %v", err)
@@ -315,16 +845,7 @@ func testCredentialsRequest(t *testing.T) *minterv1.CredentialsRequest {
 	}
 	awsProvSpec, err := codec.EncodeProviderSpec(
 		&minterv1.AWSProviderSpec{
-			StatementEntries: []minterv1.StatementEntry{
-				{
-					Effect: "Allow",
-					Action: []string{
-						"s3:CreateBucket",
-						"s3:DeleteBucket",
-					},
-					Resource: "*",
-				},
-			},
+			StatementEntries: statements,
 		})
 	if err != nil {
 		t.Logf("error encoding: %v", err)
@@ -360,6 +881,102 @@ func testCredentialsRequest(t *testing.T) *minterv1.CredentialsRequest {
 	}
 }
 
+// testSTSCredentialsRequest builds a CredentialsRequest satisfied via sts:AssumeRole rather than
+// an IAM user. expiration is nil for a CredentialsRequest that hasn't been provisioned yet.
+func testSTSCredentialsRequest(t *testing.T, expiration *metav1.Time) *minterv1.CredentialsRequest {
+	codec, err := minterv1.NewCodec()
+	if err != nil {
+		t.Logf("error creating new codec: %v", err)
+		t.FailNow()
+		return nil
+	}
+	stsProvSpec, err := codec.EncodeProviderSpec(
+		&minterv1.AWSSTSProviderSpec{
+			RoleARN: testSTSRoleARN,
+		})
+	if err != nil {
+		t.Logf("error encoding: %v", err)
+		t.FailNow()
+		return nil
+	}
+	stsStatus, err := codec.EncodeProviderStatus(
+		&minterv1.AWSProviderStatus{
+			Expiration: expiration,
+		})
+	if err != nil {
+		t.Logf("error encoding: %v", err)
+		t.FailNow()
+		return nil
+	}
+	return &minterv1.CredentialsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testCRName,
+			Namespace:   testNamespace,
+			Finalizers:  []string{minterv1.FinalizerDeprovision},
+			UID:         types.UID("1234"),
+			Annotations: map[string]string{},
+		},
+		Spec: minterv1.CredentialsRequestSpec{
+			ClusterName:  testClusterName,
+			ClusterID:    testClusterID,
+			SecretRef:    corev1.ObjectReference{Name: testSecretName, Namespace: testSecretNamespace},
+			ProviderSpec: stsProvSpec,
+		},
+		Status: minterv1.CredentialsRequestStatus{
+			ProviderStatus: stsStatus,
+		},
+	}
+}
+
+// testAzureCredentialsRequest builds a CredentialsRequest satisfied via an Azure AD service
+// principal. objectID is empty for a CredentialsRequest that hasn't been provisioned yet.
+func testAzureCredentialsRequest(t *testing.T, objectID string) *minterv1.CredentialsRequest {
+	codec, err := minterv1.NewCodec()
+	if err != nil {
+		t.Logf("error creating new codec: %v", err)
+		t.FailNow()
+		return nil
+	}
+	azureProvSpec, err := codec.EncodeProviderSpec(
+		&minterv1.AzureProviderSpec{
+			SubscriptionID:  testAzureSubscriptionID,
+			RoleDefinitions: []string{"Contributor"},
+		})
+	if err != nil {
+		t.Logf("error encoding: %v", err)
+		t.FailNow()
+		return nil
+	}
+	azureStatus, err := codec.EncodeProviderStatus(
+		&minterv1.AzureProviderStatus{
+			AppID:    testAzureAppID,
+			ObjectID: objectID,
+		})
+	if err != nil {
+		t.Logf("error encoding: %v", err)
+		t.FailNow()
+		return nil
+	}
+	return &minterv1.CredentialsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testCRName,
+			Namespace:   testNamespace,
+			Finalizers:  []string{minterv1.FinalizerDeprovision},
+			UID:         types.UID("1234"),
+			Annotations: map[string]string{},
+		},
+		Spec: minterv1.CredentialsRequestSpec{
+			ClusterName:  testClusterName,
+			ClusterID:    testClusterID,
+			SecretRef:    corev1.ObjectReference{Name: testSecretName, Namespace: testSecretNamespace},
+			ProviderSpec: azureProvSpec,
+		},
+		Status: minterv1.CredentialsRequestStatus{
+			ProviderStatus: azureStatus,
+		},
+	}
+}
+
 func createTestNamespace(namespace string) *corev1.Namespace {
 	return &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -382,6 +999,20 @@ func testAWSCredsSecret(namespace, name, accessKeyID, secretAccessKey string) *c
 	return s
 }
 
+func testAzureCredsSecret(namespace, name, tenantID, clientID, clientSecret string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			"azure_tenant_id":     []byte(tenantID),
+			"azure_client_id":     []byte(clientID),
+			"azure_client_secret": []byte(clientSecret),
+		},
+	}
+}
+
 func mockGetUserNotFound(mockAWSClient *mockaws.MockClient) {
 	mockAWSClient.EXPECT().GetUser(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "no such entity", nil))
 }
@@ -470,10 +1101,149 @@ func mockDeleteAccessKey(mockAWSClient *mockaws.MockClient, accessKeyID string)
 			AccessKeyId: aws.String(accessKeyID),
 		}).Return(&iam.DeleteAccessKeyOutput{}, nil)
 }
+
 func mockPutUserPolicy(mockAWSClient *mockaws.MockClient) {
+	mockGetUserPolicyNotFound(mockAWSClient)
 	mockAWSClient.EXPECT().PutUserPolicy(gomock.Any()).Return(&iam.PutUserPolicyOutput{}, nil)
 }
 
+func mockGetUserPolicyNotFound(mockAWSClient *mockaws.MockClient) {
+	mockAWSClient.EXPECT().GetUserPolicy(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "no such entity", nil))
+}
+
+// mockPutUserPolicyWithStatements expects a PutUserPolicy call whose PolicyDocument matches the
+// document the actuator would build for statements.
+func mockPutUserPolicyWithStatements(mockAWSClient *mockaws.MockClient, statements []minterv1.StatementEntry) {
+	doc, err := json.Marshal(buildTestPolicyDocument(statements))
+	if err != nil {
+		panic(err)
+	}
+	mockAWSClient.EXPECT().PutUserPolicy(&iam.PutUserPolicyInput{
+		UserName:       aws.String(testAWSUser),
+		PolicyName:     aws.String(fmt.Sprintf("%s-policy", testAWSUser)),
+		PolicyDocument: aws.String(string(doc)),
+	}).Return(&iam.PutUserPolicyOutput{}, nil)
+}
+
+func mockGetUserPolicyMatching(mockAWSClient *mockaws.MockClient, userName string, statements []minterv1.StatementEntry) {
+	doc, err := json.Marshal(buildTestPolicyDocument(statements))
+	if err != nil {
+		panic(err)
+	}
+	mockAWSClient.EXPECT().GetUserPolicy(&iam.GetUserPolicyInput{
+		UserName:   aws.String(userName),
+		PolicyName: aws.String(fmt.Sprintf("%s-policy", userName)),
+	}).Return(&iam.GetUserPolicyOutput{PolicyDocument: aws.String(string(doc))}, nil)
+}
+
+// testPolicyDocument and testPolicyStatement mirror actuator.policyDocument/policyStatement's
+// field order and JSON tags exactly, so tests can construct the same bytes the actuator would,
+// without importing its unexported internals.
+type testPolicyDocument struct {
+	Version   string                `json:"Version"`
+	Statement []testPolicyStatement `json:"Statement"`
+}
+
+type testPolicyStatement struct {
+	Effect    string                      `json:"Effect"`
+	Action    []string                    `json:"Action"`
+	Resource  string                      `json:"Resource"`
+	Condition minterv1.IAMPolicyCondition `json:"Condition,omitempty"`
+}
+
+func buildTestPolicyDocument(statements []minterv1.StatementEntry) testPolicyDocument {
+	doc := testPolicyDocument{
+		Version:   "2012-10-17",
+		Statement: make([]testPolicyStatement, 0, len(statements)),
+	}
+	for _, s := range statements {
+		doc.Statement = append(doc.Statement, testPolicyStatement{
+			Effect:    s.Effect,
+			Action:    s.Action,
+			Resource:  s.Resource,
+			Condition: s.PolicyCondition,
+		})
+	}
+	return doc
+}
+
+func mockGetCallerIdentity(mockAWSClient *mockaws.MockClient) {
+	mockAWSClient.EXPECT().GetCallerIdentity(gomock.Any()).Return(
+		&sts.GetCallerIdentityOutput{
+			Arn: aws.String(testRootPrincipalARN),
+		}, nil)
+}
+
+// mockSimulatePrincipalPolicy expects a SimulatePrincipalPolicy call and reports every requested
+// action as allowed if allowed is true, denied otherwise.
+func mockSimulatePrincipalPolicy(mockAWSClient *mockaws.MockClient, allowed bool) {
+	mockAWSClient.EXPECT().SimulatePrincipalPolicy(gomock.Any()).DoAndReturn(
+		func(input *iam.SimulatePrincipalPolicyInput) (*iam.SimulatePrincipalPolicyOutput, error) {
+			decision := "explicitDeny"
+			if allowed {
+				decision = "allowed"
+			}
+			results := make([]*iam.EvaluationResult, 0, len(input.ActionNames))
+			for _, action := range input.ActionNames {
+				results = append(results, &iam.EvaluationResult{
+					EvalActionName: action,
+					EvalDecision:   aws.String(decision),
+				})
+			}
+			return &iam.SimulatePrincipalPolicyOutput{EvaluationResults: results}, nil
+		})
+}
+
+func mockAssumeRole(mockAWSClient *mockaws.MockClient, expiration time.Time) {
+	mockAWSClient.EXPECT().AssumeRole(gomock.Any()).Return(
+		&sts.AssumeRoleOutput{
+			Credentials: &sts.Credentials{
+				AccessKeyId:     aws.String(testSTSAccessKeyID),
+				SecretAccessKey: aws.String(testSTSSecretAccessKey),
+				SessionToken:    aws.String(testSTSSessionToken),
+				Expiration:      aws.Time(expiration),
+			},
+		}, nil)
+}
+
+func mockCreateApplication(mockAzureClient *mockazure.MockClient, appID string) {
+	mockAzureClient.EXPECT().CreateApplication(gomock.Any(), gomock.Any()).Return(
+		graphrbac.Application{AppID: to.StringPtr(appID), ObjectID: to.StringPtr(testAzureObjectID)}, nil)
+}
+
+func mockGetApplication(mockAzureClient *mockazure.MockClient, objectID string) {
+	mockAzureClient.EXPECT().GetApplication(gomock.Any(), objectID).Return(
+		graphrbac.Application{ObjectID: to.StringPtr(objectID)}, nil)
+}
+
+func mockDeleteApplication(mockAzureClient *mockazure.MockClient, objectID string) {
+	mockAzureClient.EXPECT().DeleteApplication(gomock.Any(), objectID).Return(nil)
+}
+
+func mockCreateServicePrincipal(mockAzureClient *mockazure.MockClient, appID string) {
+	mockAzureClient.EXPECT().CreateServicePrincipal(gomock.Any(), gomock.Any()).Return(
+		graphrbac.ServicePrincipal{AppID: to.StringPtr(appID), ObjectID: to.StringPtr("test-sp-object-id")}, nil)
+}
+
+func mockCreateRoleAssignment(mockAzureClient *mockazure.MockClient) {
+	mockAzureClient.EXPECT().CreateRoleAssignment(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		authorization.RoleAssignment{ID: to.StringPtr("test-role-assignment-id")}, nil)
+}
+
+// decodeAWSProviderStatusOrFail decodes cr's ProviderStatus as an AWSProviderStatus, for tests
+// that need to inspect fields (like Expiration) the controller's Reconcile doesn't surface itself.
+func decodeAWSProviderStatusOrFail(t *testing.T, cr *minterv1.CredentialsRequest) *minterv1.AWSProviderStatus {
+	codec, err := minterv1.NewCodec()
+	if err != nil {
+		t.Fatalf("error creating codec: %v", err)
+	}
+	awsStatus := &minterv1.AWSProviderStatus{}
+	if err := codec.DecodeProviderStatus(cr.Status.ProviderStatus, awsStatus); err != nil {
+		t.Fatalf("error decoding AWS provider status: %v", err)
+	}
+	return awsStatus
+}
+
 func base64DecodeOrFail(t *testing.T, data []byte) string {
 	decoded, err := base64.StdEncoding.DecodeString(string(data))
 	if err != nil {
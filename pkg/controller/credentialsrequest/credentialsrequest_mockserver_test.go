@@ -0,0 +1,238 @@
+/*
+Copyright 2018 The OpenShift Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialsrequest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/cred-minter/pkg/apis"
+	minterv1 "github.com/openshift/cred-minter/pkg/apis/credminter/v1beta1"
+	minteraws "github.com/openshift/cred-minter/pkg/aws"
+	awsactuator "github.com/openshift/cred-minter/pkg/aws/actuator"
+	"github.com/openshift/cred-minter/pkg/aws/mockserver"
+)
+
+// TestCredentialsRequestReconcileAgainstMockServer runs the AWS actuator against a mockserver.Server
+// instead of the gomock MockClient, so these cases exercise the real aws-sdk-go request/response
+// marshaling and the mock server's modeling of AWS behavior (persistent users, the per-user access
+// key cap) rather than a hand-written interface stub.
+func TestCredentialsRequestReconcileAgainstMockServer(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	newReconciler := func(fakeClient client.Client, mockServer *mockserver.Server, codec *minterv1.Codec) *ReconcileCredentialsRequest {
+		return &ReconcileCredentialsRequest{
+			Client: fakeClient,
+			Codec:  codec,
+			Actuators: []Actuator{
+				&awsactuator.AWSActuator{
+					Client: fakeClient,
+					Codec:  codec,
+					Scheme: scheme.Scheme,
+					AWSClientBuilder: func(accessKeyID, secretAccessKey []byte) (minteraws.Client, error) {
+						return mockServer.Client()
+					},
+				},
+			},
+		}
+	}
+
+	reconcileTestCR := func(t *testing.T, rcr *ReconcileCredentialsRequest) (reconcile.Result, error) {
+		return rcr.Reconcile(reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      testCRName,
+				Namespace: testNamespace,
+			},
+		})
+	}
+
+	getSecret := func(c client.Client) *corev1.Secret {
+		secret := &corev1.Secret{}
+		if err := c.Get(context.TODO(), client.ObjectKey{Name: testSecretName, Namespace: testSecretNamespace}, secret); err != nil {
+			return nil
+		}
+		return secret
+	}
+
+	getCR := func(c client.Client) *minterv1.CredentialsRequest {
+		cr := &minterv1.CredentialsRequest{}
+		if err := c.Get(context.TODO(), client.ObjectKey{Name: testCRName, Namespace: testNamespace}, cr); err != nil {
+			return nil
+		}
+		return cr
+	}
+
+	t.Run("create against a fresh server", func(t *testing.T) {
+		mockServer := mockserver.New()
+		defer mockServer.Close()
+
+		codec, err := minterv1.NewCodec()
+		if err != nil {
+			t.Fatalf("error creating codec: %v", err)
+		}
+		fakeClient := fake.NewFakeClient(
+			createTestNamespace(testSecretNamespace),
+			testCredentialsRequest(t),
+			testAWSCredsSecret("kube-system", "aws-creds", "akeyid", "secretaccess"),
+		)
+		rcr := newReconciler(fakeClient, mockServer, codec)
+
+		if _, err := reconcileTestCR(t, rcr); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		cr := getCR(fakeClient)
+		assert.NotNil(t, cr)
+		assert.True(t, cr.Status.Provisioned)
+
+		targetSecret := getSecret(fakeClient)
+		if assert.NotNil(t, targetSecret) {
+			accessKeyID := base64DecodeOrFail(t, targetSecret.Data["aws_access_key_id"])
+			assert.NotEmpty(t, accessKeyID)
+			assert.NotEmpty(t, base64DecodeOrFail(t, targetSecret.Data["aws_secret_access_key"]))
+			assert.Equal(t, 1, mockServer.AccessKeyCount(testAWSUser))
+		}
+
+		// Reconciling again against the now-provisioned user/key must be a no-op: the real IAM
+		// request/response round trip has to agree that the existing key is still valid, or this
+		// would otherwise rotate a key on every reconcile.
+		beforeSecret := getSecret(fakeClient)
+		if _, err := reconcileTestCR(t, rcr); err != nil {
+			t.Fatalf("unexpected error on second reconcile: %v", err)
+		}
+		afterSecret := getSecret(fakeClient)
+		assert.Equal(t, beforeSecret.Data["aws_access_key_id"], afterSecret.Data["aws_access_key_id"])
+		assert.Equal(t, 1, mockServer.AccessKeyCount(testAWSUser))
+	})
+
+	t.Run("rotation clears stale keys before hitting the per-user limit", func(t *testing.T) {
+		mockServer := mockserver.New()
+		defer mockServer.Close()
+
+		// Seed the server with a user that already has the maximum two access keys, neither of
+		// which match what's in the target Secret. The actuator's rotation path must delete both
+		// stale keys before minting a replacement, rather than ever attempting a third CreateAccessKey
+		// while two are still outstanding.
+		mockServer.SeedUser(testAWSUser)
+		if _, _, err := mockServer.SeedAccessKey(testAWSUser); err != nil {
+			t.Fatalf("error seeding access key: %v", err)
+		}
+		if _, _, err := mockServer.SeedAccessKey(testAWSUser); err != nil {
+			t.Fatalf("error seeding access key: %v", err)
+		}
+
+		codec, err := minterv1.NewCodec()
+		if err != nil {
+			t.Fatalf("error creating codec: %v", err)
+		}
+		fakeClient := fake.NewFakeClient(
+			createTestNamespace(testSecretNamespace),
+			testCredentialsRequest(t),
+			testAWSCredsSecret("kube-system", "aws-creds", "akeyid", "secretaccess"),
+			testAWSCredsSecret(testNamespace, testSecretName, "stale-access-key-id", "stale-secret"),
+		)
+		rcr := newReconciler(fakeClient, mockServer, codec)
+
+		if _, err := reconcileTestCR(t, rcr); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assert.Equal(t, 1, mockServer.AccessKeyCount(testAWSUser))
+		targetSecret := getSecret(fakeClient)
+		if assert.NotNil(t, targetSecret) {
+			assert.NotEqual(t, "stale-access-key-id", base64DecodeOrFail(t, targetSecret.Data["aws_access_key_id"]))
+		}
+	})
+
+	t.Run("failure deleting a stale key surfaces an error instead of minting a third key", func(t *testing.T) {
+		mockServer := mockserver.New()
+		defer mockServer.Close()
+
+		mockServer.SeedUser(testAWSUser)
+		if _, _, err := mockServer.SeedAccessKey(testAWSUser); err != nil {
+			t.Fatalf("error seeding access key: %v", err)
+		}
+		if _, _, err := mockServer.SeedAccessKey(testAWSUser); err != nil {
+			t.Fatalf("error seeding access key: %v", err)
+		}
+		// A non-retryable error code: the SDK retries Throttling, and the injected failure is
+		// consumed after one use, so a retried call would succeed.
+		mockServer.FailNextAction("DeleteAccessKey", mockserver.ErrCodeLimitExceeded, "quota exceeded")
+
+		codec, err := minterv1.NewCodec()
+		if err != nil {
+			t.Fatalf("error creating codec: %v", err)
+		}
+		fakeClient := fake.NewFakeClient(
+			createTestNamespace(testSecretNamespace),
+			testCredentialsRequest(t),
+			testAWSCredsSecret("kube-system", "aws-creds", "akeyid", "secretaccess"),
+			testAWSCredsSecret(testNamespace, testSecretName, "stale-access-key-id", "stale-secret"),
+		)
+		rcr := newReconciler(fakeClient, mockServer, codec)
+
+		_, err = reconcileTestCR(t, rcr)
+		assert.Error(t, err)
+		// Still two keys: the reconciler must bail out on the first DeleteAccessKey failure rather
+		// than pressing ahead to CreateAccessKey while both stale keys are still outstanding.
+		assert.Equal(t, 2, mockServer.AccessKeyCount(testAWSUser))
+	})
+
+	t.Run("matching key at the access key limit is kept without rotation", func(t *testing.T) {
+		mockServer := mockserver.New()
+		defer mockServer.Close()
+
+		// A user with two keys that *do* match the target Secret's recorded key shouldn't trigger
+		// rotation at all, but if the actuator ever did attempt a create here, the server's quota
+		// enforcement must be what stops it, never a silent third key.
+		mockServer.SeedUser(testAWSUser)
+		keyID, keySecret, err := mockServer.SeedAccessKey(testAWSUser)
+		if err != nil {
+			t.Fatalf("error seeding access key: %v", err)
+		}
+		if _, _, err := mockServer.SeedAccessKey(testAWSUser); err != nil {
+			t.Fatalf("error seeding access key: %v", err)
+		}
+
+		codec, err := minterv1.NewCodec()
+		if err != nil {
+			t.Fatalf("error creating codec: %v", err)
+		}
+		fakeClient := fake.NewFakeClient(
+			createTestNamespace(testSecretNamespace),
+			testCredentialsRequest(t),
+			testAWSCredsSecret("kube-system", "aws-creds", "akeyid", "secretaccess"),
+			testAWSCredsSecret(testNamespace, testSecretName, keyID, keySecret),
+		)
+		rcr := newReconciler(fakeClient, mockServer, codec)
+
+		if _, err := reconcileTestCR(t, rcr); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, 2, mockServer.AccessKeyCount(testAWSUser))
+	})
+}